@@ -0,0 +1,99 @@
+// Package attrs builds attribute lists in hot paths with pooled buffers
+// instead of a fresh []attribute.KeyValue per call.
+package attrs
+
+import (
+	"sync"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// maxPooledCap keeps builders that grew unusually large out of the pool.
+const maxPooledCap = 64
+
+var pool = sync.Pool{
+	New: func() any {
+		return &Builder{kvs: make([]attribute.KeyValue, 0, 8)}
+	},
+}
+
+// Builder accumulates attributes. Builders come from New and go back to the
+// pool when one of Build, Set or Apply is called; they must not be used
+// afterwards.
+//
+//	span.SetAttributes(attrs.New().Str("user.id", id).Int("items", n).Build()...)
+type Builder struct {
+	kvs []attribute.KeyValue
+}
+
+// New returns an empty Builder from the pool.
+func New() *Builder {
+	return pool.Get().(*Builder)
+}
+
+func (b *Builder) Str(key, value string) *Builder {
+	b.kvs = append(b.kvs, attribute.String(key, value))
+	return b
+}
+
+func (b *Builder) Int(key string, value int) *Builder {
+	b.kvs = append(b.kvs, attribute.Int(key, value))
+	return b
+}
+
+func (b *Builder) Int64(key string, value int64) *Builder {
+	b.kvs = append(b.kvs, attribute.Int64(key, value))
+	return b
+}
+
+func (b *Builder) Float64(key string, value float64) *Builder {
+	b.kvs = append(b.kvs, attribute.Float64(key, value))
+	return b
+}
+
+func (b *Builder) Bool(key string, value bool) *Builder {
+	b.kvs = append(b.kvs, attribute.Bool(key, value))
+	return b
+}
+
+// Attrs adds prebuilt attributes.
+func (b *Builder) Attrs(kvs ...attribute.KeyValue) *Builder {
+	b.kvs = append(b.kvs, kvs...)
+	return b
+}
+
+// Build returns the attributes in a slice of exactly their length, the only
+// allocation of a pooled builder, and releases the builder.
+func (b *Builder) Build() []attribute.KeyValue {
+	kvs := make([]attribute.KeyValue, len(b.kvs))
+	copy(kvs, b.kvs)
+	b.release()
+
+	return kvs
+}
+
+// Set returns the attributes as an attribute.Set, e.g. for
+// metric.WithAttributeSet, and releases the builder.
+func (b *Builder) Set() attribute.Set {
+	set := attribute.NewSet(b.kvs...)
+	b.release()
+
+	return set
+}
+
+// Apply sets the attributes on span, which copies them, and releases the
+// builder without allocating a slice.
+func (b *Builder) Apply(span trace.Span) {
+	span.SetAttributes(b.kvs...)
+	b.release()
+}
+
+func (b *Builder) release() {
+	if cap(b.kvs) > maxPooledCap {
+		return
+	}
+	clear(b.kvs)
+	b.kvs = b.kvs[:0]
+	pool.Put(b)
+}
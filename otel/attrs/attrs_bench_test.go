@@ -0,0 +1,93 @@
+package attrs_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/0x5w4/go-otel/otel/attrs"
+	"go.opentelemetry.io/otel/attribute"
+	sdkTrace "go.opentelemetry.io/otel/sdk/trace"
+)
+
+var (
+	sinkKVs []attribute.KeyValue
+	sinkSet attribute.Set
+)
+
+func BenchmarkBuild(b *testing.B) {
+	b.ReportAllocs()
+	for b.Loop() {
+		sinkKVs = attrs.New().
+			Str("user.id", "42").
+			Int("items", 3).
+			Float64("total", 9.99).
+			Bool("retry", false).
+			Build()
+	}
+}
+
+func BenchmarkBuildSlice(b *testing.B) {
+	b.ReportAllocs()
+	for b.Loop() {
+		sinkKVs = []attribute.KeyValue{
+			attribute.String("user.id", "42"),
+			attribute.Int("items", 3),
+			attribute.Float64("total", 9.99),
+			attribute.Bool("retry", false),
+		}
+	}
+}
+
+func BenchmarkSet(b *testing.B) {
+	b.ReportAllocs()
+	for b.Loop() {
+		sinkSet = attrs.New().
+			Str("user.id", "42").
+			Int("items", 3).
+			Float64("total", 9.99).
+			Bool("retry", false).
+			Set()
+	}
+}
+
+func BenchmarkSetSlice(b *testing.B) {
+	b.ReportAllocs()
+	for b.Loop() {
+		sinkSet = attribute.NewSet(
+			attribute.String("user.id", "42"),
+			attribute.Int("items", 3),
+			attribute.Float64("total", 9.99),
+			attribute.Bool("retry", false),
+		)
+	}
+}
+
+func BenchmarkApply(b *testing.B) {
+	_, span := sdkTrace.NewTracerProvider().Tracer("bench").Start(context.Background(), "bench")
+	defer span.End()
+
+	b.ReportAllocs()
+	for b.Loop() {
+		attrs.New().
+			Str("user.id", "42").
+			Int("items", 3).
+			Float64("total", 9.99).
+			Bool("retry", false).
+			Apply(span)
+	}
+}
+
+func BenchmarkApplySlice(b *testing.B) {
+	_, span := sdkTrace.NewTracerProvider().Tracer("bench").Start(context.Background(), "bench")
+	defer span.End()
+
+	b.ReportAllocs()
+	for b.Loop() {
+		span.SetAttributes(
+			attribute.String("user.id", "42"),
+			attribute.Int("items", 3),
+			attribute.Float64("total", 9.99),
+			attribute.Bool("retry", false),
+		)
+	}
+}
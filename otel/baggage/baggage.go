@@ -0,0 +1,97 @@
+package baggage
+
+import (
+	"context"
+
+	"go.opentelemetry.io/otel/attribute"
+	otelBaggage "go.opentelemetry.io/otel/baggage"
+	sdkTrace "go.opentelemetry.io/otel/sdk/trace"
+)
+
+// Set returns a copy of ctx whose baggage has key set to value. Invalid
+// keys or values leave the baggage unchanged.
+func Set(ctx context.Context, key, value string) context.Context {
+	member, err := otelBaggage.NewMemberRaw(key, value)
+	if err != nil {
+		return ctx
+	}
+
+	b, err := otelBaggage.FromContext(ctx).SetMember(member)
+	if err != nil {
+		return ctx
+	}
+
+	return otelBaggage.ContextWithBaggage(ctx, b)
+}
+
+// Get returns the baggage value of key in ctx, or an empty string.
+func Get(ctx context.Context, key string) string {
+	return otelBaggage.FromContext(ctx).Member(key).Value()
+}
+
+// Delete returns a copy of ctx whose baggage no longer has key.
+func Delete(ctx context.Context, key string) context.Context {
+	return otelBaggage.ContextWithBaggage(ctx, otelBaggage.FromContext(ctx).DeleteMember(key))
+}
+
+// All returns every baggage member in ctx.
+func All(ctx context.Context) map[string]string {
+	members := otelBaggage.FromContext(ctx).Members()
+
+	values := make(map[string]string, len(members))
+	for _, m := range members {
+		values[m.Key()] = m.Value()
+	}
+
+	return values
+}
+
+var _ sdkTrace.SpanProcessor = (*spanProcessor)(nil)
+
+type spanProcessor struct {
+	keys       []string
+	attributes []attribute.Key
+}
+
+// NewSpanProcessor returns a span processor that copies the baggage members
+// named by keys onto every span as it starts. Members missing from the
+// context are skipped.
+func NewSpanProcessor(keys ...string) sdkTrace.SpanProcessor {
+	return NewPrefixedSpanProcessor("", keys...)
+}
+
+// NewPrefixedSpanProcessor is like NewSpanProcessor but records the members
+// under their key with prefix prepended, e.g. "baggage." to record tenant_id
+// as baggage.tenant_id, keeping them apart from the attributes set by the
+// application.
+func NewPrefixedSpanProcessor(prefix string, keys ...string) sdkTrace.SpanProcessor {
+	attributes := make([]attribute.Key, len(keys))
+	for i, key := range keys {
+		attributes[i] = attribute.Key(prefix + key)
+	}
+
+	return &spanProcessor{keys: keys, attributes: attributes}
+}
+
+func (p *spanProcessor) OnStart(parent context.Context, s sdkTrace.ReadWriteSpan) {
+	b := otelBaggage.FromContext(parent)
+	if b.Len() == 0 {
+		return
+	}
+
+	for i, key := range p.keys {
+		if m := b.Member(key); m.Key() != "" {
+			s.SetAttributes(p.attributes[i].String(m.Value()))
+		}
+	}
+}
+
+func (p *spanProcessor) OnEnd(s sdkTrace.ReadOnlySpan) {}
+
+func (p *spanProcessor) Shutdown(ctx context.Context) error {
+	return nil
+}
+
+func (p *spanProcessor) ForceFlush(ctx context.Context) error {
+	return nil
+}
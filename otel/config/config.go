@@ -0,0 +1,419 @@
+// Package config loads a telemetry configuration file shared by services and
+// turns it into the configurations of the tracer, meter and logger packages.
+//
+// Files are YAML; since JSON is a subset of YAML, JSON files are accepted as
+// well. References to environment variables, ${NAME} or ${NAME:-default},
+// are replaced before the file is parsed, and $$ stands for a literal $.
+// Durations are written as "5s" or "1m30s". A minimal file looks like:
+//
+//	service:
+//	  name: checkout
+//	  environment: ${DEPLOY_ENV:-dev}
+//	exporter:
+//	  endpoint: ${OTEL_COLLECTOR:-localhost:4317}
+//	  token: ${OTEL_TOKEN}
+//	tracer:
+//	  sampler:
+//	    spec: parentbased_traceidratio:0.1
+//	    rules:
+//	      - match: "POST /checkout"
+//	        ratio: 1
+//	    drop_span_names: ["GET /healthz"]
+//	  processors:
+//	    redact_attribute_keys: ["*password*"]
+//	metrics:
+//	  runtime: true
+package config
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"maps"
+	"os"
+	"regexp"
+	"time"
+
+	"go.yaml.in/yaml/v3"
+
+	"github.com/0x5w4/go-otel/otel/logger"
+	"github.com/0x5w4/go-otel/otel/meter"
+	"github.com/0x5w4/go-otel/otel/tracer"
+)
+
+// Config is the content of a configuration file. Exporter settings apply to
+// every signal and are overridden field by field by the exporter of a
+// signal section.
+type Config struct {
+	Service  Service  `yaml:"service"`
+	Exporter Exporter `yaml:"exporter"`
+	Tracer   Tracer   `yaml:"tracer"`
+	Metrics  Metrics  `yaml:"metrics"`
+	Logs     Logs     `yaml:"logs"`
+}
+
+type Service struct {
+	Name        string `yaml:"name"`
+	Version     string `yaml:"version"`
+	Environment string `yaml:"environment"`
+	// Attributes are extra resource attributes of the traces.
+	Attributes map[string]string `yaml:"attributes"`
+}
+
+type Exporter struct {
+	Endpoint string            `yaml:"endpoint"`
+	Token    string            `yaml:"token"`
+	Headers  map[string]string `yaml:"headers"`
+	// Protocol, Compression, Insecure and TLS only apply to traces.
+	Protocol    string `yaml:"protocol"`
+	Compression string `yaml:"compression"`
+	Insecure    bool   `yaml:"insecure"`
+	TLS         TLS    `yaml:"tls"`
+}
+
+type TLS struct {
+	CAFile             string `yaml:"ca_file"`
+	CertFile           string `yaml:"cert_file"`
+	KeyFile            string `yaml:"key_file"`
+	ServerName         string `yaml:"server_name"`
+	InsecureSkipVerify bool   `yaml:"insecure_skip_verify"`
+}
+
+type Tracer struct {
+	Exporter               Exporter       `yaml:"exporter"`
+	Sampler                Sampler        `yaml:"sampler"`
+	Processors             Processors     `yaml:"processors"`
+	Batch                  Batch          `yaml:"batch"`
+	Retry                  *Retry         `yaml:"retry"`
+	Failover               Failover       `yaml:"failover"`
+	CircuitBreaker         CircuitBreaker `yaml:"circuit_breaker"`
+	Spillover              Spillover      `yaml:"spillover"`
+	Propagators            []string       `yaml:"propagators"`
+	ResourceDetectors      []string       `yaml:"resource_detectors"`
+	BaggageKeys            []string       `yaml:"baggage_keys"`
+	BaggageAttributePrefix string         `yaml:"baggage_attribute_prefix"`
+	XRayCompatible         bool           `yaml:"xray_compatible"`
+	ProfilingLabels        bool           `yaml:"profiling_labels"`
+	Debug                  bool           `yaml:"debug"`
+}
+
+type Sampler struct {
+	// Spec is a sampler spec as accepted by tracer.ParseSampler.
+	Spec                 string         `yaml:"spec"`
+	TargetSpansPerSecond float64        `yaml:"target_spans_per_second"`
+	Rules                []SamplingRule `yaml:"rules"`
+	DropSpanNames        []string       `yaml:"drop_span_names"`
+	TailSamplingWindow   time.Duration  `yaml:"tail_sampling_window"`
+	TailSamplingLatency  time.Duration  `yaml:"tail_sampling_latency"`
+}
+
+type SamplingRule struct {
+	Match string  `yaml:"match"`
+	Ratio float64 `yaml:"ratio"`
+}
+
+type Processors struct {
+	RedactAttributeKeys     []string `yaml:"redact_attribute_keys"`
+	HashAttributeKeys       []string `yaml:"hash_attribute_keys"`
+	MaxAttributeCardinality int      `yaml:"max_attribute_cardinality"`
+	// DefaultNormalizeRules adds tracer.DefaultNormalizeRules before
+	// NormalizeRules.
+	DefaultNormalizeRules bool            `yaml:"default_normalize_rules"`
+	NormalizeRules        []NormalizeRule `yaml:"normalize_rules"`
+	SetOKOnEnd            bool            `yaml:"set_ok_on_end"`
+	MaxTraceDuration      time.Duration   `yaml:"max_trace_duration"`
+	MaxEventsWarn         int             `yaml:"max_events_warn"`
+	ExportFilter          ExportFilter    `yaml:"export_filter"`
+}
+
+// ExportFilter filters spans by scope and span name before export, see
+// tracer.ExportFilter.
+type ExportFilter struct {
+	AllowScopes    []string `yaml:"allow_scopes"`
+	DenyScopes     []string `yaml:"deny_scopes"`
+	AllowSpanNames []string `yaml:"allow_span_names"`
+	DenySpanNames  []string `yaml:"deny_span_names"`
+}
+
+type NormalizeRule struct {
+	// Pattern is a regular expression.
+	Pattern     string `yaml:"pattern"`
+	Replacement string `yaml:"replacement"`
+}
+
+type Batch struct {
+	MaxQueueSize       int           `yaml:"max_queue_size"`
+	MaxExportBatchSize int           `yaml:"max_export_batch_size"`
+	BatchTimeout       time.Duration `yaml:"batch_timeout"`
+	ExportTimeout      time.Duration `yaml:"export_timeout"`
+}
+
+type Retry struct {
+	Enabled         bool          `yaml:"enabled"`
+	InitialInterval time.Duration `yaml:"initial_interval"`
+	MaxInterval     time.Duration `yaml:"max_interval"`
+	MaxElapsedTime  time.Duration `yaml:"max_elapsed_time"`
+}
+
+// Failover configures a secondary collector for the traces, see
+// tracer.Config.FailoverURL.
+type Failover struct {
+	Endpoint     string        `yaml:"endpoint"`
+	Threshold    int           `yaml:"threshold"`
+	RetryPrimary time.Duration `yaml:"retry_primary"`
+}
+
+// CircuitBreaker configures the circuit breaker around the trace exporter,
+// see tracer.Config.CircuitBreakerThreshold.
+type CircuitBreaker struct {
+	Threshold int           `yaml:"threshold"`
+	Cooldown  time.Duration `yaml:"cooldown"`
+}
+
+// Spillover configures the disk buffer of the traces, see
+// tracer.Config.SpilloverDir.
+type Spillover struct {
+	Dir           string        `yaml:"dir"`
+	MaxBytes      int64         `yaml:"max_bytes"`
+	RetryInterval time.Duration `yaml:"retry_interval"`
+}
+
+type Metrics struct {
+	Exporter   Exporter      `yaml:"exporter"`
+	Interval   time.Duration `yaml:"interval"`
+	Timeout    time.Duration `yaml:"timeout"`
+	Runtime    bool          `yaml:"runtime"`
+	Host       bool          `yaml:"host"`
+	Prometheus bool          `yaml:"prometheus"`
+	Views      []View        `yaml:"views"`
+}
+
+type View struct {
+	Instrument    string    `yaml:"instrument"`
+	Rename        string    `yaml:"rename"`
+	Buckets       []float64 `yaml:"buckets"`
+	AttributeKeys []string  `yaml:"attribute_keys"`
+}
+
+type Logs struct {
+	Exporter Exporter `yaml:"exporter"`
+}
+
+// LoadFile reads and parses the configuration file at path.
+func LoadFile(path string) (*Config, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read config file: %w", err)
+	}
+
+	cfg, err := Parse(data)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse config file %s: %w", path, err)
+	}
+
+	return cfg, nil
+}
+
+// Parse parses a YAML or JSON configuration after expanding environment
+// variable references. Unknown keys are rejected so typos do not go
+// unnoticed.
+func Parse(data []byte) (*Config, error) {
+	decoder := yaml.NewDecoder(bytes.NewReader(expandEnv(data)))
+	decoder.KnownFields(true)
+
+	cfg := new(Config)
+	if err := decoder.Decode(cfg); err != nil && !errors.Is(err, io.EOF) {
+		return nil, err
+	}
+
+	return cfg, nil
+}
+
+var envReference = regexp.MustCompile(`\$\$|\$\{([A-Za-z_][A-Za-z0-9_]*)(:-([^}]*))?\}`)
+
+// expandEnv replaces ${NAME} and ${NAME:-default} with the value of the
+// environment variable NAME, using default when it is unset or empty, and
+// $$ with $.
+func expandEnv(data []byte) []byte {
+	return envReference.ReplaceAllFunc(data, func(ref []byte) []byte {
+		if string(ref) == "$$" {
+			return []byte("$")
+		}
+		m := envReference.FindSubmatch(ref)
+		if value := os.Getenv(string(m[1])); value != "" {
+			return []byte(value)
+		}
+		return m[3]
+	})
+}
+
+// exporter merges the exporter of a signal section over the shared one.
+func (c *Config) exporter(signal Exporter) Exporter {
+	e := c.Exporter
+	if signal.Endpoint != "" {
+		e.Endpoint = signal.Endpoint
+	}
+	if signal.Token != "" {
+		e.Token = signal.Token
+	}
+	if len(signal.Headers) > 0 {
+		headers := maps.Clone(e.Headers)
+		if headers == nil {
+			headers = make(map[string]string, len(signal.Headers))
+		}
+		maps.Copy(headers, signal.Headers)
+		e.Headers = headers
+	}
+	if signal.Protocol != "" {
+		e.Protocol = signal.Protocol
+	}
+	if signal.Compression != "" {
+		e.Compression = signal.Compression
+	}
+	if signal.Insecure {
+		e.Insecure = true
+	}
+	if signal.TLS != (TLS{}) {
+		e.TLS = signal.TLS
+	}
+
+	return e
+}
+
+// TracerConfig returns the tracer configuration described by the service,
+// exporter and tracer sections. Pass it to tracer.InitTracer, optionally
+// followed by options for the fields files cannot express, such as hooks.
+func (c *Config) TracerConfig() (*tracer.Config, error) {
+	e := c.exporter(c.Tracer.Exporter)
+	t := c.Tracer
+
+	cfg := &tracer.Config{
+		ExporterURL:           e.Endpoint,
+		SecretToken:           e.Token,
+		Headers:               e.Headers,
+		Protocol:              e.Protocol,
+		Compression:           e.Compression,
+		Insecure:              e.Insecure,
+		TLSCAFile:             e.TLS.CAFile,
+		TLSCertFile:           e.TLS.CertFile,
+		TLSKeyFile:            e.TLS.KeyFile,
+		TLSServerName:         e.TLS.ServerName,
+		TLSInsecureSkipVerify: e.TLS.InsecureSkipVerify,
+
+		ServiceName:           c.Service.Name,
+		ServiceVersion:        c.Service.Version,
+		DeploymentEnvironment: c.Service.Environment,
+		ResourceAttributes:    c.Service.Attributes,
+
+		SamplerSpec:          t.Sampler.Spec,
+		TargetSpansPerSecond: t.Sampler.TargetSpansPerSecond,
+		DropSpanNames:        t.Sampler.DropSpanNames,
+		TailSamplingWindow:   t.Sampler.TailSamplingWindow,
+		TailSamplingLatency:  t.Sampler.TailSamplingLatency,
+
+		RedactAttributeKeys:     t.Processors.RedactAttributeKeys,
+		HashAttributeKeys:       t.Processors.HashAttributeKeys,
+		MaxAttributeCardinality: t.Processors.MaxAttributeCardinality,
+		SetOKOnEnd:              t.Processors.SetOKOnEnd,
+		MaxTraceDuration:        t.Processors.MaxTraceDuration,
+		MaxEventsWarn:           t.Processors.MaxEventsWarn,
+		ExportFilter:            tracer.ExportFilter(t.Processors.ExportFilter),
+
+		FailoverURL:          t.Failover.Endpoint,
+		FailoverThreshold:    t.Failover.Threshold,
+		FailoverRetryPrimary: t.Failover.RetryPrimary,
+
+		CircuitBreakerThreshold: t.CircuitBreaker.Threshold,
+		CircuitBreakerCooldown:  t.CircuitBreaker.Cooldown,
+
+		SpilloverDir:           t.Spillover.Dir,
+		SpilloverMaxBytes:      t.Spillover.MaxBytes,
+		SpilloverRetryInterval: t.Spillover.RetryInterval,
+
+		MaxQueueSize:       t.Batch.MaxQueueSize,
+		MaxExportBatchSize: t.Batch.MaxExportBatchSize,
+		BatchTimeout:       t.Batch.BatchTimeout,
+		ExportTimeout:      t.Batch.ExportTimeout,
+
+		Propagators:            t.Propagators,
+		ResourceDetectors:      t.ResourceDetectors,
+		BaggageKeys:            t.BaggageKeys,
+		BaggageAttributePrefix: t.BaggageAttributePrefix,
+		XRayCompatible:         t.XRayCompatible,
+		ProfilingLabels:        t.ProfilingLabels,
+		Debug:                  t.Debug,
+	}
+
+	for _, rule := range t.Sampler.Rules {
+		cfg.SamplingRules = append(cfg.SamplingRules, tracer.SamplingRule{Match: rule.Match, Ratio: rule.Ratio})
+	}
+
+	if t.Processors.DefaultNormalizeRules {
+		cfg.NormalizeRules = append(cfg.NormalizeRules, tracer.DefaultNormalizeRules...)
+	}
+	for _, rule := range t.Processors.NormalizeRules {
+		pattern, err := regexp.Compile(rule.Pattern)
+		if err != nil {
+			return nil, fmt.Errorf("invalid normalize rule pattern %q: %w", rule.Pattern, err)
+		}
+		cfg.NormalizeRules = append(cfg.NormalizeRules, tracer.NormalizeRule{Pattern: pattern, Replacement: rule.Replacement})
+	}
+
+	if t.Retry != nil {
+		cfg.Retry = &tracer.RetryConfig{
+			Enabled:         t.Retry.Enabled,
+			InitialInterval: t.Retry.InitialInterval,
+			MaxInterval:     t.Retry.MaxInterval,
+			MaxElapsedTime:  t.Retry.MaxElapsedTime,
+		}
+	}
+
+	return cfg, nil
+}
+
+// MeterConfig returns the meter configuration described by the service,
+// exporter and metrics sections.
+func (c *Config) MeterConfig() *meter.Config {
+	e := c.exporter(c.Metrics.Exporter)
+	m := c.Metrics
+
+	cfg := &meter.Config{
+		ExporterURL:           e.Endpoint,
+		SecretToken:           e.Token,
+		Headers:               e.Headers,
+		ServiceName:           c.Service.Name,
+		ServiceVersion:        c.Service.Version,
+		DeploymentEnvironment: c.Service.Environment,
+		Interval:              m.Interval,
+		Timeout:               m.Timeout,
+		RuntimeMetrics:        m.Runtime,
+		HostMetrics:           m.Host,
+		Prometheus:            m.Prometheus,
+	}
+	for _, v := range m.Views {
+		cfg.ViewConfigs = append(cfg.ViewConfigs, meter.ViewConfig{
+			Instrument:    v.Instrument,
+			Rename:        v.Rename,
+			Buckets:       v.Buckets,
+			AttributeKeys: v.AttributeKeys,
+		})
+	}
+
+	return cfg
+}
+
+// LoggerConfig returns the logger configuration described by the service,
+// exporter and logs sections.
+func (c *Config) LoggerConfig() *logger.Config {
+	e := c.exporter(c.Logs.Exporter)
+
+	return &logger.Config{
+		ExporterURL:           e.Endpoint,
+		SecretToken:           e.Token,
+		Headers:               e.Headers,
+		ServiceName:           c.Service.Name,
+		ServiceVersion:        c.Service.Version,
+		DeploymentEnvironment: c.Service.Environment,
+	}
+}
@@ -0,0 +1,162 @@
+package amqpotel
+
+import (
+	"context"
+	"maps"
+
+	amqp "github.com/rabbitmq/amqp091-go"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/baggage"
+	"go.opentelemetry.io/otel/codes"
+	semconv "go.opentelemetry.io/otel/semconv/v1.20.0"
+	"go.opentelemetry.io/otel/trace"
+
+	"github.com/0x5w4/go-otel/otel/messaging"
+)
+
+const instrumentationName = "github.com/0x5w4/go-otel/otel/instrument/amqp"
+
+// Channel wraps an amqp091-go channel so that messages are published under
+// producer spans carrying their context in the message headers, and
+// processed under consumer spans linked to the span that published them.
+// The other methods of amqp.Channel are available unchanged.
+type Channel struct {
+	*amqp.Channel
+	attrs  []attribute.KeyValue
+	tracer trace.Tracer
+}
+
+// NewChannel wraps ch.
+func NewChannel(ch *amqp.Channel, opts ...Option) *Channel {
+	c := &Channel{Channel: ch}
+	for _, opt := range opts {
+		opt(c)
+	}
+	if c.tracer == nil {
+		c.tracer = otel.Tracer(instrumentationName)
+	}
+
+	return c
+}
+
+// PublishWithContext is amqp.Channel.PublishWithContext under a producer
+// span, which records the error of the publish.
+func (c *Channel) PublishWithContext(ctx context.Context, exchange, key string, mandatory, immediate bool, msg amqp.Publishing) error {
+	ctx, span := c.StartPublish(ctx, exchange, key, &msg)
+	defer span.End()
+
+	err := c.Channel.PublishWithContext(ctx, exchange, key, mandatory, immediate, msg)
+	recordError(span, err)
+
+	return err
+}
+
+// StartPublish starts a producer span for msg, to be published to exchange
+// with routing key key, and injects its context into the message headers.
+// The headers are copied first, so a Table shared between messages is not
+// modified. Use it for the publish methods PublishWithContext does not
+// cover, such as PublishWithDeferredConfirmWithContext.
+func (c *Channel) StartPublish(ctx context.Context, exchange, key string, msg *amqp.Publishing) (context.Context, trace.Span) {
+	destination := exchange
+	if destination == "" {
+		// The default exchange routes by queue name.
+		destination = key
+	}
+
+	attrs := make([]attribute.KeyValue, 0, len(c.attrs)+7)
+	attrs = append(attrs, c.attrs...)
+	attrs = append(attrs,
+		semconv.MessagingSystem("rabbitmq"),
+		semconv.MessagingOperationPublish,
+		semconv.MessagingDestinationName(exchange),
+		semconv.MessagingRabbitmqDestinationRoutingKey(key),
+		semconv.MessagingMessagePayloadSizeBytes(len(msg.Body)),
+	)
+	attrs = append(attrs, messageAttributes(msg.MessageId, msg.CorrelationId)...)
+
+	ctx, span := c.tracer.Start(ctx, destination+" publish",
+		trace.WithSpanKind(trace.SpanKindProducer),
+		trace.WithAttributes(attrs...),
+	)
+
+	msg.Headers = maps.Clone(msg.Headers)
+	if msg.Headers == nil {
+		msg.Headers = make(amqp.Table)
+	}
+	otel.GetTextMapPropagator().Inject(ctx, messaging.AnyMapCarrier(msg.Headers))
+
+	return ctx, span
+}
+
+// StartProcess starts a consumer span for processing d, received from
+// queue. The span continues the trace of ctx and links to the producer
+// span found in the message headers, whose baggage is added to ctx.
+func (c *Channel) StartProcess(ctx context.Context, queue string, d amqp.Delivery) (context.Context, trace.Span) {
+	attrs := make([]attribute.KeyValue, 0, len(c.attrs)+8)
+	attrs = append(attrs, c.attrs...)
+	attrs = append(attrs,
+		semconv.MessagingSystem("rabbitmq"),
+		semconv.MessagingOperationProcess,
+		semconv.MessagingSourceName(queue),
+		semconv.MessagingDestinationName(d.Exchange),
+		semconv.MessagingRabbitmqDestinationRoutingKey(d.RoutingKey),
+		semconv.MessagingMessagePayloadSizeBytes(len(d.Body)),
+	)
+	attrs = append(attrs, messageAttributes(d.MessageId, d.CorrelationId)...)
+
+	opts := []trace.SpanStartOption{
+		trace.WithSpanKind(trace.SpanKindConsumer),
+		trace.WithAttributes(attrs...),
+	}
+
+	producer := otel.GetTextMapPropagator().Extract(context.Background(), messaging.AnyMapCarrier(d.Headers))
+	if sc := trace.SpanContextFromContext(producer); sc.IsValid() {
+		opts = append(opts, trace.WithLinks(trace.Link{SpanContext: sc}))
+	}
+	if b := baggage.FromContext(producer); b.Len() > 0 {
+		ctx = baggage.ContextWithBaggage(ctx, b)
+	}
+
+	return c.tracer.Start(ctx, queue+" process", opts...)
+}
+
+// ConsumeFunc starts consuming queue as ConsumeWithContext does and calls
+// handler for every delivery under a span started by StartProcess, until
+// the deliveries stop because ctx is done or the channel is closed. Errors
+// returned by handler are recorded on the span; acknowledging deliveries is
+// left to handler unless autoAck is set. It only fails when the consumer
+// cannot be started.
+func (c *Channel) ConsumeFunc(ctx context.Context, queue, consumer string, autoAck, exclusive, noLocal, noWait bool, args amqp.Table, handler func(ctx context.Context, d amqp.Delivery) error) error {
+	deliveries, err := c.Channel.ConsumeWithContext(ctx, queue, consumer, autoAck, exclusive, noLocal, noWait, args)
+	if err != nil {
+		return err
+	}
+
+	for d := range deliveries {
+		dctx, span := c.StartProcess(ctx, queue, d)
+		recordError(span, handler(dctx, d))
+		span.End()
+	}
+
+	return nil
+}
+
+func messageAttributes(messageID, correlationID string) []attribute.KeyValue {
+	var attrs []attribute.KeyValue
+	if messageID != "" {
+		attrs = append(attrs, semconv.MessagingMessageID(messageID))
+	}
+	if correlationID != "" {
+		attrs = append(attrs, semconv.MessagingMessageConversationID(correlationID))
+	}
+
+	return attrs
+}
+
+func recordError(span trace.Span, err error) {
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	}
+}
@@ -0,0 +1,54 @@
+package graphqlotel
+
+import (
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+
+	"github.com/0x5w4/go-otel/otel/tracer"
+)
+
+type Option func(t *Tracer)
+
+// WithAttributes configures attributes that are used to create a span.
+func WithAttributes(attrs ...attribute.KeyValue) Option {
+	return func(t *Tracer) {
+		t.attrs = append(t.attrs, attrs...)
+	}
+}
+
+// WithTracer returns an Option to create spans with the TracerProvider of
+// a tracer initialized by this module.
+func WithTracer(t tracer.Tracer) Option {
+	return func(tr *Tracer) {
+		if t != nil {
+			WithTracerProvider(t.TracerProvider())(tr)
+		}
+	}
+}
+
+// WithTracerProvider returns an Option to use the TracerProvider when
+// creating a Tracer.
+func WithTracerProvider(tp trace.TracerProvider) Option {
+	return func(t *Tracer) {
+		if tp != nil {
+			t.tracer = tp.Tracer(instrumentationName)
+		}
+	}
+}
+
+// WithDocument records the query document of every operation in the
+// graphql.document attribute. It is off by default since documents may
+// carry inlined arguments.
+func WithDocument() Option {
+	return func(t *Tracer) {
+		t.document = true
+	}
+}
+
+// WithTrivialFields also traces fields without a resolver of their own,
+// which are plain struct field reads and multiply the span count.
+func WithTrivialFields() Option {
+	return func(t *Tracer) {
+		t.trivialFields = true
+	}
+}
@@ -0,0 +1,148 @@
+package graphqlotel
+
+import (
+	"context"
+
+	"github.com/99designs/gqlgen/graphql"
+	"github.com/99designs/gqlgen/graphql/handler/extension"
+	"github.com/vektah/gqlparser/v2/gqlerror"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	semconv "go.opentelemetry.io/otel/semconv/v1.20.0"
+	"go.opentelemetry.io/otel/trace"
+)
+
+const instrumentationName = "github.com/0x5w4/go-otel/otel/instrument/graphql"
+
+const (
+	complexityKey  = attribute.Key("graphql.operation.complexity")
+	fieldNameKey   = attribute.Key("graphql.field.name")
+	fieldPathKey   = attribute.Key("graphql.field.path")
+	fieldObjectKey = attribute.Key("graphql.field.object")
+	errorPathKey   = attribute.Key("graphql.error.path")
+)
+
+// defaultOperation names the spans of anonymous operations.
+const defaultOperation = "GraphQL Operation"
+
+// Tracer is a gqlgen handler extension that records every operation as a
+// span named after the operation, with its type, name and complexity, and
+// every resolver as a child span named Object.field. GraphQL errors are
+// recorded on the span of the operation or field they belong to. Register
+// it with server.Use.
+type Tracer struct {
+	attrs         []attribute.KeyValue
+	tracer        trace.Tracer
+	document      bool
+	trivialFields bool
+}
+
+var (
+	_ graphql.HandlerExtension    = (*Tracer)(nil)
+	_ graphql.ResponseInterceptor = (*Tracer)(nil)
+	_ graphql.FieldInterceptor    = (*Tracer)(nil)
+)
+
+func NewTracer(opts ...Option) *Tracer {
+	t := new(Tracer)
+	for _, opt := range opts {
+		opt(t)
+	}
+	if t.tracer == nil {
+		t.tracer = otel.Tracer(instrumentationName)
+	}
+	return t
+}
+
+func (t *Tracer) ExtensionName() string {
+	return "OpenTelemetry"
+}
+
+func (t *Tracer) Validate(graphql.ExecutableSchema) error {
+	return nil
+}
+
+func (t *Tracer) InterceptResponse(ctx context.Context, next graphql.ResponseHandler) *graphql.Response {
+	if !graphql.HasOperationContext(ctx) {
+		return next(ctx)
+	}
+	opCtx := graphql.GetOperationContext(ctx)
+
+	name := opCtx.OperationName
+	if name == "" {
+		name = defaultOperation
+	}
+
+	attrs := make([]attribute.KeyValue, 0, len(t.attrs)+4)
+	attrs = append(attrs, t.attrs...)
+	if opCtx.OperationName != "" {
+		attrs = append(attrs, semconv.GraphqlOperationName(opCtx.OperationName))
+	}
+	if opCtx.Operation != nil {
+		attrs = append(attrs, semconv.GraphqlOperationTypeKey.String(string(opCtx.Operation.Operation)))
+	}
+	if t.document {
+		attrs = append(attrs, semconv.GraphqlDocument(opCtx.RawQuery))
+	}
+	if stats := extension.GetComplexityStats(ctx); stats != nil {
+		attrs = append(attrs, complexityKey.Int(stats.Complexity))
+	}
+
+	startOpts := []trace.SpanStartOption{
+		trace.WithSpanKind(trace.SpanKindServer),
+		trace.WithAttributes(attrs...),
+	}
+	if !opCtx.Stats.OperationStart.IsZero() {
+		startOpts = append(startOpts, trace.WithTimestamp(opCtx.Stats.OperationStart))
+	}
+
+	ctx, span := t.tracer.Start(ctx, name, startOpts...)
+	defer span.End()
+
+	resp := next(ctx)
+	if resp != nil {
+		recordErrors(span, resp.Errors)
+	}
+
+	return resp
+}
+
+func (t *Tracer) InterceptField(ctx context.Context, next graphql.Resolver) (any, error) {
+	fc := graphql.GetFieldContext(ctx)
+	if fc == nil || (!fc.IsResolver && !t.trivialFields) {
+		return next(ctx)
+	}
+
+	ctx, span := t.tracer.Start(ctx, fc.Object+"."+fc.Field.Name,
+		trace.WithAttributes(
+			fieldObjectKey.String(fc.Object),
+			fieldNameKey.String(fc.Field.Name),
+			fieldPathKey.String(fc.Path().String()),
+		),
+	)
+	defer span.End()
+
+	// gqlgen adds the error of the resolver to the field errors only after
+	// the interceptors return.
+	res, err := next(ctx)
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	}
+
+	return res, err
+}
+
+// recordErrors records GraphQL errors as exception events with their path
+// and sets the span status from the first one.
+func recordErrors(span trace.Span, errs gqlerror.List) {
+	if len(errs) == 0 {
+		return
+	}
+
+	for _, err := range errs {
+		span.RecordError(err, trace.WithAttributes(errorPathKey.String(err.Path.String())))
+	}
+	span.SetStatus(codes.Error, errs[0].Message)
+}
@@ -0,0 +1,21 @@
+package hostotel
+
+import (
+	"go.opentelemetry.io/otel/metric"
+)
+
+type Option func(c *config)
+
+type config struct {
+	meterProvider metric.MeterProvider
+}
+
+// WithMeterProvider returns an Option to record the metrics with the
+// MeterProvider instead of the global one.
+func WithMeterProvider(mp metric.MeterProvider) Option {
+	return func(c *config) {
+		if mp != nil {
+			c.meterProvider = mp
+		}
+	}
+}
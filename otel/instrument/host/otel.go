@@ -0,0 +1,88 @@
+// Package hostotel records basic machine health metrics: CPU time, memory
+// usage and utilization, network and disk I/O, for services deployed
+// without a node agent.
+package hostotel
+
+import (
+	"context"
+	"fmt"
+	"math"
+
+	"github.com/shirou/gopsutil/v4/disk"
+	otelHost "go.opentelemetry.io/contrib/instrumentation/host"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/metric"
+)
+
+const instrumentationName = "github.com/0x5w4/go-otel/otel/instrument/host"
+
+var (
+	directionRead  = attribute.String("disk.io.direction", "read")
+	directionWrite = attribute.String("disk.io.direction", "write")
+)
+
+// Start records the process and system CPU, memory and network metrics of
+// the contrib host instrumentation, and system.disk.io and
+// system.disk.operations per device.
+func Start(opts ...Option) error {
+	c := config{meterProvider: otel.GetMeterProvider()}
+	for _, opt := range opts {
+		opt(&c)
+	}
+
+	if err := otelHost.Start(otelHost.WithMeterProvider(c.meterProvider)); err != nil {
+		return fmt.Errorf("failed to start host metrics: %w", err)
+	}
+
+	if err := startDisk(c.meterProvider.Meter(instrumentationName)); err != nil {
+		return fmt.Errorf("failed to start disk metrics: %w", err)
+	}
+
+	return nil
+}
+
+func startDisk(m metric.Meter) error {
+	bytes, err := m.Int64ObservableCounter("system.disk.io",
+		metric.WithDescription("Bytes read from and written to disk."),
+		metric.WithUnit("By"),
+	)
+	if err != nil {
+		return err
+	}
+
+	operations, err := m.Int64ObservableCounter("system.disk.operations",
+		metric.WithDescription("Disk read and write operations."),
+		metric.WithUnit("{operation}"),
+	)
+	if err != nil {
+		return err
+	}
+
+	_, err = m.RegisterCallback(func(ctx context.Context, o metric.Observer) error {
+		counters, err := disk.IOCountersWithContext(ctx)
+		if err != nil {
+			return fmt.Errorf("failed to read disk counters: %w", err)
+		}
+
+		for name, c := range counters {
+			device := attribute.String("system.device", name)
+			o.ObserveInt64(bytes, clamp(c.ReadBytes), metric.WithAttributes(device, directionRead))
+			o.ObserveInt64(bytes, clamp(c.WriteBytes), metric.WithAttributes(device, directionWrite))
+			o.ObserveInt64(operations, clamp(c.ReadCount), metric.WithAttributes(device, directionRead))
+			o.ObserveInt64(operations, clamp(c.WriteCount), metric.WithAttributes(device, directionWrite))
+		}
+
+		return nil
+	}, bytes, operations)
+
+	return err
+}
+
+func clamp(v uint64) int64 {
+	if v > math.MaxInt64 {
+		return math.MaxInt64
+	}
+
+	return int64(v)
+}
@@ -0,0 +1,124 @@
+package kafkaotel
+
+import (
+	"github.com/IBM/sarama"
+	"github.com/segmentio/kafka-go"
+	"go.opentelemetry.io/otel/propagation"
+)
+
+var (
+	_ propagation.TextMapCarrier = (*SaramaProducerCarrier)(nil)
+	_ propagation.TextMapCarrier = (*SaramaConsumerCarrier)(nil)
+	_ propagation.TextMapCarrier = (*KafkaGoCarrier)(nil)
+)
+
+// SaramaProducerCarrier adapts the headers of a sarama.ProducerMessage.
+type SaramaProducerCarrier struct {
+	msg *sarama.ProducerMessage
+}
+
+func NewSaramaProducerCarrier(msg *sarama.ProducerMessage) *SaramaProducerCarrier {
+	return &SaramaProducerCarrier{msg: msg}
+}
+
+func (c *SaramaProducerCarrier) Get(key string) string {
+	for _, h := range c.msg.Headers {
+		if string(h.Key) == key {
+			return string(h.Value)
+		}
+	}
+	return ""
+}
+
+func (c *SaramaProducerCarrier) Set(key, value string) {
+	for i, h := range c.msg.Headers {
+		if string(h.Key) == key {
+			c.msg.Headers[i].Value = []byte(value)
+			return
+		}
+	}
+	c.msg.Headers = append(c.msg.Headers, sarama.RecordHeader{Key: []byte(key), Value: []byte(value)})
+}
+
+func (c *SaramaProducerCarrier) Keys() []string {
+	keys := make([]string, 0, len(c.msg.Headers))
+	for _, h := range c.msg.Headers {
+		keys = append(keys, string(h.Key))
+	}
+	return keys
+}
+
+// SaramaConsumerCarrier adapts the headers of a sarama.ConsumerMessage.
+type SaramaConsumerCarrier struct {
+	msg *sarama.ConsumerMessage
+}
+
+func NewSaramaConsumerCarrier(msg *sarama.ConsumerMessage) *SaramaConsumerCarrier {
+	return &SaramaConsumerCarrier{msg: msg}
+}
+
+func (c *SaramaConsumerCarrier) Get(key string) string {
+	for _, h := range c.msg.Headers {
+		if h != nil && string(h.Key) == key {
+			return string(h.Value)
+		}
+	}
+	return ""
+}
+
+func (c *SaramaConsumerCarrier) Set(key, value string) {
+	for _, h := range c.msg.Headers {
+		if h != nil && string(h.Key) == key {
+			h.Value = []byte(value)
+			return
+		}
+	}
+	c.msg.Headers = append(c.msg.Headers, &sarama.RecordHeader{Key: []byte(key), Value: []byte(value)})
+}
+
+func (c *SaramaConsumerCarrier) Keys() []string {
+	keys := make([]string, 0, len(c.msg.Headers))
+	for _, h := range c.msg.Headers {
+		if h != nil {
+			keys = append(keys, string(h.Key))
+		}
+	}
+	return keys
+}
+
+// KafkaGoCarrier adapts the headers of a segmentio/kafka-go Message, for
+// both writing and reading.
+type KafkaGoCarrier struct {
+	msg *kafka.Message
+}
+
+func NewKafkaGoCarrier(msg *kafka.Message) *KafkaGoCarrier {
+	return &KafkaGoCarrier{msg: msg}
+}
+
+func (c *KafkaGoCarrier) Get(key string) string {
+	for _, h := range c.msg.Headers {
+		if h.Key == key {
+			return string(h.Value)
+		}
+	}
+	return ""
+}
+
+func (c *KafkaGoCarrier) Set(key, value string) {
+	for i, h := range c.msg.Headers {
+		if h.Key == key {
+			c.msg.Headers[i].Value = []byte(value)
+			return
+		}
+	}
+	c.msg.Headers = append(c.msg.Headers, kafka.Header{Key: key, Value: []byte(value)})
+}
+
+func (c *KafkaGoCarrier) Keys() []string {
+	keys := make([]string, 0, len(c.msg.Headers))
+	for _, h := range c.msg.Headers {
+		keys = append(keys, h.Key)
+	}
+	return keys
+}
@@ -0,0 +1,70 @@
+package kafkaotel
+
+import (
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/trace"
+
+	"github.com/0x5w4/go-otel/otel/tracer"
+)
+
+type config struct {
+	attrs      []attribute.KeyValue
+	tracer     trace.Tracer
+	propagator propagation.TextMapPropagator
+}
+
+type Option func(c *config)
+
+func newConfig(opts []Option) *config {
+	c := new(config)
+	for _, opt := range opts {
+		opt(c)
+	}
+	if c.tracer == nil {
+		c.tracer = otel.Tracer(instrumentationName)
+	}
+	if c.propagator == nil {
+		c.propagator = otel.GetTextMapPropagator()
+	}
+
+	return c
+}
+
+// WithAttributes configures attributes that are used to create a span.
+func WithAttributes(attrs ...attribute.KeyValue) Option {
+	return func(c *config) {
+		c.attrs = append(c.attrs, attrs...)
+	}
+}
+
+// WithTracer returns an Option to create spans with the TracerProvider of
+// a tracer initialized by this module and to propagate the message context
+// with its propagator.
+func WithTracer(t tracer.Tracer) Option {
+	return func(c *config) {
+		if t != nil {
+			WithTracerProvider(t.TracerProvider())(c)
+			WithPropagator(t.Propagator())(c)
+		}
+	}
+}
+
+// WithTracerProvider returns an Option to use the TracerProvider when
+// creating a Tracer.
+func WithTracerProvider(tp trace.TracerProvider) Option {
+	return func(c *config) {
+		if tp != nil {
+			c.tracer = tp.Tracer(instrumentationName)
+		}
+	}
+}
+
+// WithPropagator configures the propagator used for message headers
+// instead of the global one.
+func WithPropagator(p propagation.TextMapPropagator) Option {
+	return func(c *config) {
+		c.propagator = p
+	}
+}
@@ -0,0 +1,81 @@
+package kafkaotel
+
+import (
+	"context"
+
+	"github.com/IBM/sarama"
+	"github.com/segmentio/kafka-go"
+	"go.opentelemetry.io/otel/propagation"
+	semconv "go.opentelemetry.io/otel/semconv/v1.20.0"
+	"go.opentelemetry.io/otel/trace"
+)
+
+const instrumentationName = "github.com/0x5w4/go-otel/otel/instrument/kafka"
+
+// StartPublish starts a producer span for a message to topic and injects
+// its context into carrier. End the span once the message is acknowledged.
+func StartPublish(ctx context.Context, carrier propagation.TextMapCarrier, topic string, opts ...Option) (context.Context, trace.Span) {
+	c := newConfig(opts)
+
+	ctx, span := c.tracer.Start(ctx, topic+" publish",
+		trace.WithSpanKind(trace.SpanKindProducer),
+		trace.WithAttributes(
+			semconv.MessagingSystem("kafka"),
+			semconv.MessagingOperationPublish,
+			semconv.MessagingDestinationNameKey.String(topic),
+		),
+		trace.WithAttributes(c.attrs...),
+	)
+	c.propagator.Inject(ctx, carrier)
+
+	return ctx, span
+}
+
+// StartProcess extracts the producer context from carrier and starts a
+// consumer span for processing a message from topic as its child.
+func StartProcess(ctx context.Context, carrier propagation.TextMapCarrier, topic string, opts ...Option) (context.Context, trace.Span) {
+	c := newConfig(opts)
+	ctx = c.propagator.Extract(ctx, carrier)
+
+	return c.tracer.Start(ctx, topic+" process",
+		trace.WithSpanKind(trace.SpanKindConsumer),
+		trace.WithAttributes(
+			semconv.MessagingSystem("kafka"),
+			semconv.MessagingOperationProcess,
+			semconv.MessagingSourceNameKey.String(topic),
+		),
+		trace.WithAttributes(c.attrs...),
+	)
+}
+
+// StartSaramaPublish is StartPublish for a sarama producer message.
+func StartSaramaPublish(ctx context.Context, msg *sarama.ProducerMessage, opts ...Option) (context.Context, trace.Span) {
+	return StartPublish(ctx, NewSaramaProducerCarrier(msg), msg.Topic, opts...)
+}
+
+// StartSaramaProcess is StartProcess for a sarama consumer message.
+func StartSaramaProcess(ctx context.Context, msg *sarama.ConsumerMessage, opts ...Option) (context.Context, trace.Span) {
+	return StartProcess(ctx, NewSaramaConsumerCarrier(msg), msg.Topic, append([]Option{WithAttributes(
+		semconv.MessagingKafkaSourcePartitionKey.Int(int(msg.Partition)),
+		semconv.MessagingKafkaMessageOffsetKey.Int64(msg.Offset),
+	)}, opts...)...)
+}
+
+// StartKafkaGoPublish is StartPublish for a kafka-go message. kafka-go
+// rejects messages that repeat the topic of their Writer, so the topic is
+// passed separately; an empty topic falls back to msg.Topic.
+func StartKafkaGoPublish(ctx context.Context, msg *kafka.Message, topic string, opts ...Option) (context.Context, trace.Span) {
+	if topic == "" {
+		topic = msg.Topic
+	}
+
+	return StartPublish(ctx, NewKafkaGoCarrier(msg), topic, opts...)
+}
+
+// StartKafkaGoProcess is StartProcess for a message read by kafka-go.
+func StartKafkaGoProcess(ctx context.Context, msg *kafka.Message, opts ...Option) (context.Context, trace.Span) {
+	return StartProcess(ctx, NewKafkaGoCarrier(msg), msg.Topic, append([]Option{WithAttributes(
+		semconv.MessagingKafkaSourcePartitionKey.Int(msg.Partition),
+		semconv.MessagingKafkaMessageOffsetKey.Int64(msg.Offset),
+	)}, opts...)...)
+}
@@ -0,0 +1,47 @@
+package mongootel
+
+import (
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+
+	"github.com/0x5w4/go-otel/otel/tracer"
+)
+
+type Option func(m *monitor)
+
+// WithAttributes configures attributes that are used to create a span.
+func WithAttributes(attrs ...attribute.KeyValue) Option {
+	return func(m *monitor) {
+		m.attrs = append(m.attrs, attrs...)
+	}
+}
+
+// WithTracer returns an Option to create spans with the TracerProvider of
+// a tracer initialized by this module.
+func WithTracer(t tracer.Tracer) Option {
+	return func(m *monitor) {
+		if t != nil {
+			WithTracerProvider(t.TracerProvider())(m)
+		}
+	}
+}
+
+// WithTracerProvider returns an Option to use the TracerProvider when
+// creating a Tracer.
+func WithTracerProvider(tp trace.TracerProvider) Option {
+	return func(m *monitor) {
+		if tp != nil {
+			m.tracer = tp.Tracer(instrumentationName)
+		}
+	}
+}
+
+// WithSkipCommands configures commands, e.g. the "hello" and "ping"
+// handshakes and heartbeats, that are run without a span.
+func WithSkipCommands(names ...string) Option {
+	return func(m *monitor) {
+		for _, name := range names {
+			m.skip[name] = struct{}{}
+		}
+	}
+}
@@ -0,0 +1,136 @@
+package mongootel
+
+import (
+	"context"
+	"net"
+	"strconv"
+	"strings"
+	"sync"
+
+	"go.mongodb.org/mongo-driver/v2/bson"
+	"go.mongodb.org/mongo-driver/v2/event"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	semconv "go.opentelemetry.io/otel/semconv/v1.20.0"
+	"go.opentelemetry.io/otel/trace"
+)
+
+const instrumentationName = "github.com/0x5w4/go-otel/otel/instrument/mongo"
+
+type monitor struct {
+	attrs  []attribute.KeyValue
+	tracer trace.Tracer
+	skip   map[string]struct{}
+	spans  sync.Map // spanKey -> trace.Span
+}
+
+// spanKey identifies a command between its started and finished events.
+type spanKey struct {
+	connectionID string
+	requestID    int64
+}
+
+// NewMonitor returns a command monitor for the official MongoDB driver that
+// records every command as a client span named after the command and its
+// collection, with db.name, db.operation and db.mongodb.collection. Set it
+// with options.Client().SetMonitor. Command documents are not recorded.
+func NewMonitor(opts ...Option) *event.CommandMonitor {
+	m := &monitor{skip: make(map[string]struct{})}
+	for _, opt := range opts {
+		opt(m)
+	}
+	if m.tracer == nil {
+		m.tracer = otel.Tracer(instrumentationName)
+	}
+
+	return &event.CommandMonitor{
+		Started:   m.started,
+		Succeeded: m.succeeded,
+		Failed:    m.failed,
+	}
+}
+
+func (m *monitor) started(ctx context.Context, e *event.CommandStartedEvent) {
+	if _, ok := m.skip[e.CommandName]; ok {
+		return
+	}
+
+	attrs := make([]attribute.KeyValue, 0, len(m.attrs)+6)
+	attrs = append(attrs, m.attrs...)
+	attrs = append(attrs,
+		semconv.DBSystemMongoDB,
+		semconv.DBName(e.DatabaseName),
+		semconv.DBOperationKey.String(e.CommandName),
+	)
+
+	name := e.CommandName
+	if collection := commandCollection(e.Command); collection != "" {
+		attrs = append(attrs, semconv.DBMongoDBCollection(collection))
+		name = collection + "." + e.CommandName
+	}
+	if host, port := peer(e.ConnectionID); host != "" {
+		attrs = append(attrs, semconv.NetPeerName(host))
+		if port > 0 {
+			attrs = append(attrs, semconv.NetPeerPort(port))
+		}
+	}
+
+	_, span := m.tracer.Start(ctx, name,
+		trace.WithSpanKind(trace.SpanKindClient),
+		trace.WithAttributes(attrs...),
+	)
+	m.spans.Store(spanKey{e.ConnectionID, e.RequestID}, span)
+}
+
+func (m *monitor) succeeded(ctx context.Context, e *event.CommandSucceededEvent) {
+	if span, ok := m.end(e.CommandFinishedEvent); ok {
+		span.End()
+	}
+}
+
+func (m *monitor) failed(ctx context.Context, e *event.CommandFailedEvent) {
+	if span, ok := m.end(e.CommandFinishedEvent); ok {
+		span.RecordError(e.Failure)
+		span.SetStatus(codes.Error, e.Failure.Error())
+		span.End()
+	}
+}
+
+func (m *monitor) end(e event.CommandFinishedEvent) (trace.Span, bool) {
+	v, ok := m.spans.LoadAndDelete(spanKey{e.ConnectionID, e.RequestID})
+	if !ok {
+		return nil, false
+	}
+
+	return v.(trace.Span), true
+}
+
+// commandCollection returns the collection a command targets, the string
+// value of its first element for commands such as find, insert or
+// aggregate, or an empty string for database commands.
+func commandCollection(cmd bson.Raw) string {
+	elem, err := cmd.IndexErr(0)
+	if err != nil {
+		return ""
+	}
+	collection, ok := elem.Value().StringValueOK()
+	if !ok {
+		return ""
+	}
+
+	return collection
+}
+
+// peer splits a driver connection ID such as "localhost:27017[-3]" into the
+// server host and port.
+func peer(connectionID string) (string, int) {
+	addr, _, _ := strings.Cut(connectionID, "[")
+	host, portStr, err := net.SplitHostPort(addr)
+	if err != nil {
+		return addr, 0
+	}
+	port, _ := strconv.Atoi(portStr)
+
+	return host, port
+}
@@ -0,0 +1,37 @@
+package redisotel
+
+import (
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+
+	"github.com/0x5w4/go-otel/otel/tracer"
+)
+
+type Option func(h *Hook)
+
+// WithAttributes configures attributes that are used to create a span.
+func WithAttributes(attrs ...attribute.KeyValue) Option {
+	return func(h *Hook) {
+		h.attrs = append(h.attrs, attrs...)
+	}
+}
+
+// WithTracer returns an Option to create spans with the TracerProvider of
+// a tracer initialized by this module.
+func WithTracer(t tracer.Tracer) Option {
+	return func(h *Hook) {
+		if t != nil {
+			WithTracerProvider(t.TracerProvider())(h)
+		}
+	}
+}
+
+// WithTracerProvider returns an Option to use the TracerProvider when
+// creating a Tracer.
+func WithTracerProvider(tp trace.TracerProvider) Option {
+	return func(h *Hook) {
+		if tp != nil {
+			h.tracer = tp.Tracer(instrumentationName)
+		}
+	}
+}
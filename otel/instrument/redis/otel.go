@@ -0,0 +1,131 @@
+package redisotel
+
+import (
+	"context"
+	"errors"
+	"net"
+	"strings"
+
+	"github.com/redis/go-redis/v9"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	semconv "go.opentelemetry.io/otel/semconv/v1.20.0"
+	"go.opentelemetry.io/otel/trace"
+)
+
+const instrumentationName = "github.com/0x5w4/go-otel/otel/instrument/redis"
+
+const (
+	keyCountKey     = attribute.Key("db.redis.key_count")
+	pipelineSizeKey = attribute.Key("db.redis.num_cmd")
+)
+
+// Hook is a go-redis hook that records commands, pipelines and dials as
+// client spans. Register it with client.AddHook.
+type Hook struct {
+	attrs  []attribute.KeyValue
+	tracer trace.Tracer
+}
+
+var _ redis.Hook = (*Hook)(nil)
+
+func NewHook(opts ...Option) *Hook {
+	h := new(Hook)
+	for _, opt := range opts {
+		opt(h)
+	}
+	if h.tracer == nil {
+		h.tracer = otel.Tracer(instrumentationName)
+	}
+	return h
+}
+
+func (h *Hook) DialHook(next redis.DialHook) redis.DialHook {
+	return func(ctx context.Context, network, addr string) (net.Conn, error) {
+		ctx, span := h.start(ctx, "redis.dial", semconv.NetTransportKey.String(network))
+		defer span.End()
+
+		conn, err := next(ctx, network, addr)
+		recordError(span, err)
+
+		return conn, err
+	}
+}
+
+func (h *Hook) ProcessHook(next redis.ProcessHook) redis.ProcessHook {
+	return func(ctx context.Context, cmd redis.Cmder) error {
+		ctx, span := h.start(ctx, cmd.FullName(),
+			semconv.DBOperationKey.String(cmd.Name()),
+			keyCountKey.Int(keyCount(cmd)),
+		)
+		defer span.End()
+
+		err := next(ctx, cmd)
+		recordError(span, err)
+
+		return err
+	}
+}
+
+func (h *Hook) ProcessPipelineHook(next redis.ProcessPipelineHook) redis.ProcessPipelineHook {
+	return func(ctx context.Context, cmds []redis.Cmder) error {
+		names := make([]string, 0, len(cmds))
+		keys := 0
+		for _, cmd := range cmds {
+			names = append(names, cmd.Name())
+			keys += keyCount(cmd)
+		}
+
+		ctx, span := h.start(ctx, "redis.pipeline",
+			semconv.DBOperationKey.String(strings.Join(names, " ")),
+			pipelineSizeKey.Int(len(cmds)),
+			keyCountKey.Int(keys),
+		)
+		defer span.End()
+
+		err := next(ctx, cmds)
+		recordError(span, err)
+
+		return err
+	}
+}
+
+func (h *Hook) start(ctx context.Context, name string, attrs ...attribute.KeyValue) (context.Context, trace.Span) {
+	all := make([]attribute.KeyValue, 0, len(h.attrs)+len(attrs)+1)
+	all = append(all, h.attrs...)
+	all = append(all, semconv.DBSystemRedis)
+	all = append(all, attrs...)
+
+	return h.tracer.Start(ctx, name,
+		trace.WithSpanKind(trace.SpanKindClient),
+		trace.WithAttributes(all...),
+	)
+}
+
+func recordError(span trace.Span, err error) {
+	if err == nil || errors.Is(err, redis.Nil) {
+		return
+	}
+	span.RecordError(err)
+	span.SetStatus(codes.Error, err.Error())
+}
+
+// keyCount estimates the number of keys a command touches from its
+// arguments: every argument for multi-key commands, every other one for
+// MSET-style commands, and the first one otherwise.
+func keyCount(cmd redis.Cmder) int {
+	args := len(cmd.Args()) - 1
+	if args <= 0 {
+		return 0
+	}
+
+	switch cmd.Name() {
+	case "del", "exists", "mget", "touch", "unlink", "watch", "sinter", "sunion", "sdiff", "pfcount":
+		return args
+	case "mset", "msetnx":
+		return args / 2
+	default:
+		return 1
+	}
+}
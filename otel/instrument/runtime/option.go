@@ -0,0 +1,35 @@
+package runtimeotel
+
+import (
+	"time"
+
+	"go.opentelemetry.io/otel/metric"
+)
+
+type Option func(c *config)
+
+type config struct {
+	meterProvider metric.MeterProvider
+	interval      time.Duration
+}
+
+// WithMeterProvider returns an Option to record the metrics with the
+// MeterProvider instead of the global one.
+func WithMeterProvider(mp metric.MeterProvider) Option {
+	return func(c *config) {
+		if mp != nil {
+			c.meterProvider = mp
+		}
+	}
+}
+
+// WithMinimumReadInterval configures how often the runtime statistics are
+// read at most, 15s by default. Collections in between report the last
+// values read.
+func WithMinimumReadInterval(d time.Duration) Option {
+	return func(c *config) {
+		if d > 0 {
+			c.interval = d
+		}
+	}
+}
@@ -0,0 +1,104 @@
+// Package runtimeotel records Go runtime metrics: goroutine count, heap and
+// memory statistics, GC cycles and pause time, and scheduler latency.
+package runtimeotel
+
+import (
+	"context"
+	"fmt"
+	"runtime"
+	"sync"
+	"time"
+
+	otelRuntime "go.opentelemetry.io/contrib/instrumentation/runtime"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/metric"
+	sdkMetric "go.opentelemetry.io/otel/sdk/metric"
+)
+
+const instrumentationName = "github.com/0x5w4/go-otel/otel/instrument/runtime"
+
+const defaultInterval = 15 * time.Second
+
+// Start records the runtime metrics of the contrib runtime instrumentation
+// (go.goroutine.count, go.memory.*, go.processor.limit and others) along
+// with go.gc.count and go.gc.pause.duration. Scheduler latency is a
+// histogram computed by the runtime and is exported through NewProducer
+// instead.
+func Start(opts ...Option) error {
+	c := config{meterProvider: otel.GetMeterProvider(), interval: defaultInterval}
+	for _, opt := range opts {
+		opt(&c)
+	}
+
+	if err := otelRuntime.Start(
+		otelRuntime.WithMeterProvider(c.meterProvider),
+		otelRuntime.WithMinimumReadMemStatsInterval(c.interval),
+	); err != nil {
+		return fmt.Errorf("failed to start runtime metrics: %w", err)
+	}
+
+	if err := startGC(c.meterProvider.Meter(instrumentationName), c.interval); err != nil {
+		return fmt.Errorf("failed to start gc metrics: %w", err)
+	}
+
+	return nil
+}
+
+// NewProducer returns the producer of the go.schedule.duration histogram.
+// Register it on the metric reader with sdkMetric.WithProducer.
+func NewProducer() sdkMetric.Producer {
+	return otelRuntime.NewProducer()
+}
+
+// gcStats caches the GC counters of runtime.MemStats, which stops the world
+// to read, for at least interval.
+type gcStats struct {
+	interval time.Duration
+
+	mu       sync.Mutex
+	lastRead time.Time
+	count    uint32
+	pause    time.Duration
+}
+
+func (s *gcStats) read() (uint32, time.Duration) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if now := time.Now(); now.Sub(s.lastRead) >= s.interval {
+		var m runtime.MemStats
+		runtime.ReadMemStats(&m)
+		s.count, s.pause = m.NumGC, time.Duration(m.PauseTotalNs)
+		s.lastRead = now
+	}
+
+	return s.count, s.pause
+}
+
+func startGC(m metric.Meter, interval time.Duration) error {
+	count, err := m.Int64ObservableCounter("go.gc.count",
+		metric.WithDescription("Number of completed GC cycles."),
+		metric.WithUnit("{gc_cycle}"),
+	)
+	if err != nil {
+		return err
+	}
+
+	pause, err := m.Float64ObservableCounter("go.gc.pause.duration",
+		metric.WithDescription("Cumulative time the world was stopped by the garbage collector."),
+		metric.WithUnit("s"),
+	)
+	if err != nil {
+		return err
+	}
+
+	stats := &gcStats{interval: interval}
+	_, err = m.RegisterCallback(func(ctx context.Context, o metric.Observer) error {
+		n, d := stats.read()
+		o.ObserveInt64(count, int64(n))
+		o.ObserveFloat64(pause, d.Seconds())
+		return nil
+	}, count, pause)
+
+	return err
+}
@@ -0,0 +1,114 @@
+package sqlotel
+
+import (
+	"github.com/uptrace/opentelemetry-go-extra/otelsql"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/metric"
+	"go.opentelemetry.io/otel/trace"
+
+	"github.com/0x5w4/go-otel/otel/sanitize"
+	"github.com/0x5w4/go-otel/otel/tracer"
+)
+
+type Option func(c *config)
+
+type config struct {
+	opts []otelsql.Option
+}
+
+// WithTracer returns an Option to create spans with the TracerProvider of
+// a tracer initialized by this module.
+func WithTracer(t tracer.Tracer) Option {
+	return func(c *config) {
+		if t != nil {
+			c.opts = append(c.opts, otelsql.WithTracerProvider(t.TracerProvider()))
+		}
+	}
+}
+
+// WithTracerProvider returns an Option to use the TracerProvider when
+// creating a Tracer.
+func WithTracerProvider(tp trace.TracerProvider) Option {
+	return func(c *config) {
+		if tp != nil {
+			c.opts = append(c.opts, otelsql.WithTracerProvider(tp))
+		}
+	}
+}
+
+// WithMeterProvider returns an Option to use the MeterProvider when
+// creating a Meter.
+func WithMeterProvider(mp metric.MeterProvider) Option {
+	return func(c *config) {
+		if mp != nil {
+			c.opts = append(c.opts, otelsql.WithMeterProvider(mp))
+		}
+	}
+}
+
+// WithAttributes configures attributes that are used to create a span.
+func WithAttributes(attrs ...attribute.KeyValue) Option {
+	return func(c *config) {
+		c.opts = append(c.opts, otelsql.WithAttributes(attrs...))
+	}
+}
+
+// WithDBSystem configures a db.system attribute, e.g. "postgresql".
+func WithDBSystem(system string) Option {
+	return func(c *config) {
+		c.opts = append(c.opts, otelsql.WithDBSystem(system))
+	}
+}
+
+// WithDBName configures a db.name attribute.
+func WithDBName(name string) Option {
+	return func(c *config) {
+		c.opts = append(c.opts, otelsql.WithDBName(name))
+	}
+}
+
+// WithStatementFormatter configures a function that rewrites queries
+// before they are recorded as the db.statement attribute, e.g. to strip
+// sensitive literals.
+func WithStatementFormatter(f func(query string) string) Option {
+	return func(c *config) {
+		c.opts = append(c.opts, otelsql.WithQueryFormatter(f))
+	}
+}
+
+// StatementMode selects how queries are recorded as db.statement.
+type StatementMode int
+
+const (
+	// StatementFull records queries as they are, the default.
+	StatementFull StatementMode = iota
+	// StatementObfuscated replaces the literals of queries with ?, see
+	// sanitize.SQL. For PostgreSQL use
+	// WithStatementFormatter(sanitize.DialectPostgreSQL.SQL) instead.
+	StatementObfuscated
+	// StatementOff records an empty db.statement.
+	StatementOff
+)
+
+// WithStatementMode configures how queries are recorded. Of
+// WithStatementMode and WithStatementFormatter, the last one applies.
+func WithStatementMode(mode StatementMode) Option {
+	return func(c *config) {
+		switch mode {
+		case StatementObfuscated:
+			c.opts = append(c.opts, otelsql.WithQueryFormatter(sanitize.SQL))
+		case StatementOff:
+			c.opts = append(c.opts, otelsql.WithQueryFormatter(func(string) string { return "" }))
+		default:
+			c.opts = append(c.opts, otelsql.WithQueryFormatter(nil))
+		}
+	}
+}
+
+func newConfig(opts []Option) *config {
+	c := new(config)
+	for _, opt := range opts {
+		opt(c)
+	}
+	return c
+}
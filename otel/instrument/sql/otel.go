@@ -0,0 +1,50 @@
+package sqlotel
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+
+	"github.com/uptrace/opentelemetry-go-extra/otelsql"
+)
+
+// Open is a wrapper over sql.Open that records connections, queries,
+// statements and transactions as spans, with db.statement and the query
+// duration, and reports connection pool metrics.
+func Open(driverName, dsn string, opts ...Option) (*sql.DB, error) {
+	return otelsql.Open(driverName, dsn, newConfig(opts).opts...)
+}
+
+// OpenDB is the sql.OpenDB counterpart of Open.
+func OpenDB(connector driver.Connector, opts ...Option) *sql.DB {
+	return otelsql.OpenDB(connector, newConfig(opts).opts...)
+}
+
+// OpenDriver is Open for a driver.Driver that is not registered with
+// database/sql.
+func OpenDriver(d driver.Driver, dsn string, opts ...Option) (*sql.DB, error) {
+	if dc, ok := d.(driver.DriverContext); ok {
+		connector, err := dc.OpenConnector(dsn)
+		if err != nil {
+			return nil, err
+		}
+		return OpenDB(connector, opts...), nil
+	}
+
+	return OpenDB(&dsnConnector{driver: d, dsn: dsn}, opts...), nil
+}
+
+// dsnConnector adapts a driver without connector support, like
+// database/sql does internally.
+type dsnConnector struct {
+	driver driver.Driver
+	dsn    string
+}
+
+func (c *dsnConnector) Connect(ctx context.Context) (driver.Conn, error) {
+	return c.driver.Open(c.dsn)
+}
+
+func (c *dsnConnector) Driver() driver.Driver {
+	return c.driver
+}
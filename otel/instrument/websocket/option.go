@@ -0,0 +1,47 @@
+package websocketotel
+
+import (
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/trace"
+
+	"github.com/0x5w4/go-otel/otel/tracer"
+)
+
+type Option func(c *Conn)
+
+// WithAttributes configures attributes that are used to create the
+// connection span.
+func WithAttributes(attrs ...attribute.KeyValue) Option {
+	return func(c *Conn) {
+		c.attrs = append(c.attrs, attrs...)
+	}
+}
+
+// WithTracer returns an Option to create spans with the TracerProvider of
+// a tracer initialized by this module.
+func WithTracer(t tracer.Tracer) Option {
+	return func(c *Conn) {
+		if t != nil {
+			WithTracerProvider(t.TracerProvider())(c)
+		}
+	}
+}
+
+// WithTracerProvider returns an Option to use the TracerProvider when
+// creating a Tracer.
+func WithTracerProvider(tp trace.TracerProvider) Option {
+	return func(c *Conn) {
+		if tp != nil {
+			c.tracer = tp.Tracer(instrumentationName)
+		}
+	}
+}
+
+// WithPropagator configures the propagator used for message envelopes
+// instead of the global one.
+func WithPropagator(p propagation.TextMapPropagator) Option {
+	return func(c *Conn) {
+		c.propagator = p
+	}
+}
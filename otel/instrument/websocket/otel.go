@@ -0,0 +1,158 @@
+// Package websocketotel traces long-lived connections such as websockets:
+// a span covering the whole connection and a span per message, with the
+// trace context carried in the headers of a message envelope. It works with
+// any websocket library.
+package websocketotel
+
+import (
+	"context"
+	"encoding/json"
+	"sync/atomic"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/baggage"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/propagation"
+	semconv "go.opentelemetry.io/otel/semconv/v1.20.0"
+	"go.opentelemetry.io/otel/trace"
+)
+
+const instrumentationName = "github.com/0x5w4/go-otel/otel/instrument/websocket"
+
+const (
+	messagesReceivedKey = attribute.Key("websocket.messages.received")
+	messagesSentKey     = attribute.Key("websocket.messages.sent")
+)
+
+// Envelope wraps a message payload with headers carrying the trace context
+// of its sender, for JSON encoded protocols.
+type Envelope struct {
+	Headers map[string]string `json:"headers,omitempty"`
+	Payload json.RawMessage   `json:"payload"`
+}
+
+// Carrier returns the headers of e as a propagation.TextMapCarrier.
+func (e *Envelope) Carrier() propagation.TextMapCarrier {
+	if e.Headers == nil {
+		e.Headers = make(map[string]string)
+	}
+
+	return propagation.MapCarrier(e.Headers)
+}
+
+// Conn traces one connection. Its span is the root of a trace of its own,
+// linked to the span of the request that opened the connection, so the
+// upgrade request is not stretched over the lifetime of the connection.
+type Conn struct {
+	attrs      []attribute.KeyValue
+	tracer     trace.Tracer
+	propagator propagation.TextMapPropagator
+
+	ctx      context.Context
+	span     trace.Span
+	received atomic.Int64
+	sent     atomic.Int64
+}
+
+// StartConn starts the span of a connection named name. ctx is usually the
+// context of the upgrade request; the returned Conn keeps its values but
+// not its cancellation.
+func StartConn(ctx context.Context, name string, opts ...Option) *Conn {
+	c := new(Conn)
+	for _, opt := range opts {
+		opt(c)
+	}
+	if c.tracer == nil {
+		c.tracer = otel.Tracer(instrumentationName)
+	}
+	if c.propagator == nil {
+		c.propagator = otel.GetTextMapPropagator()
+	}
+
+	startOpts := []trace.SpanStartOption{
+		trace.WithNewRoot(),
+		trace.WithSpanKind(trace.SpanKindServer),
+		trace.WithAttributes(c.attrs...),
+	}
+	if sc := trace.SpanContextFromContext(ctx); sc.IsValid() {
+		startOpts = append(startOpts, trace.WithLinks(trace.Link{SpanContext: sc}))
+	}
+	c.ctx, c.span = c.tracer.Start(context.WithoutCancel(ctx), name, startOpts...)
+
+	return c
+}
+
+// Context returns the context carrying the connection span.
+func (c *Conn) Context() context.Context {
+	return c.ctx
+}
+
+// StartReceive starts the span of a message received on the connection.
+// When carrier holds the trace context of the sender the span continues
+// that trace and links to the connection span; otherwise it is a child of
+// the connection span.
+func (c *Conn) StartReceive(name string, carrier propagation.TextMapCarrier, attrs ...attribute.KeyValue) (context.Context, trace.Span) {
+	c.received.Add(1)
+
+	ctx := c.ctx
+	startOpts := []trace.SpanStartOption{
+		trace.WithSpanKind(trace.SpanKindConsumer),
+		trace.WithAttributes(semconv.MessagingSystem("websocket"), semconv.MessagingOperationReceive),
+		trace.WithAttributes(attrs...),
+	}
+	if carrier != nil {
+		// Extracting into a context without the connection span tells a
+		// sender context apart from the connection span itself.
+		remote := c.propagator.Extract(context.Background(), carrier)
+		if sc := trace.SpanContextFromContext(remote); sc.IsRemote() {
+			ctx = trace.ContextWithRemoteSpanContext(ctx, sc)
+			if b := baggage.FromContext(remote); b.Len() > 0 {
+				ctx = baggage.ContextWithBaggage(ctx, b)
+			}
+			startOpts = append(startOpts, trace.WithLinks(trace.Link{SpanContext: c.span.SpanContext()}))
+		}
+	}
+
+	return c.tracer.Start(ctx, name, startOpts...)
+}
+
+// StartSend starts the span of a message sent on the connection and injects
+// its context into carrier. The span is a child of the span in ctx, e.g.
+// the one of a received message it answers, linked to the connection span,
+// or a child of the connection span when ctx has none.
+func (c *Conn) StartSend(ctx context.Context, name string, carrier propagation.TextMapCarrier, attrs ...attribute.KeyValue) (context.Context, trace.Span) {
+	c.sent.Add(1)
+
+	startOpts := []trace.SpanStartOption{
+		trace.WithSpanKind(trace.SpanKindProducer),
+		trace.WithAttributes(semconv.MessagingSystem("websocket"), semconv.MessagingOperationPublish),
+		trace.WithAttributes(attrs...),
+	}
+	if sc := trace.SpanContextFromContext(ctx); !sc.IsValid() {
+		ctx = c.ctx
+	} else if sc.SpanID() != c.span.SpanContext().SpanID() {
+		startOpts = append(startOpts, trace.WithLinks(trace.Link{SpanContext: c.span.SpanContext()}))
+	}
+
+	ctx, span := c.tracer.Start(ctx, name, startOpts...)
+	if carrier != nil {
+		c.propagator.Inject(ctx, carrier)
+	}
+
+	return ctx, span
+}
+
+// End ends the connection span, recording the number of messages received
+// and sent and err, the reason the connection closed, if not nil.
+func (c *Conn) End(err error) {
+	c.span.SetAttributes(
+		messagesReceivedKey.Int64(c.received.Load()),
+		messagesSentKey.Int64(c.sent.Load()),
+	)
+	if err != nil {
+		c.span.RecordError(err)
+		c.span.SetStatus(codes.Error, err.Error())
+	}
+	c.span.End()
+}
@@ -0,0 +1,105 @@
+// Package otlpconfig holds the OTLP exporter settings shared by the tracer,
+// meter and logger packages.
+package otlpconfig
+
+import (
+	"fmt"
+	"maps"
+	"net/url"
+	"os"
+	"strings"
+)
+
+// FirstEnv returns the value of the first of names that is set.
+func FirstEnv(names ...string) string {
+	for _, name := range names {
+		if v := os.Getenv(name); v != "" {
+			return v
+		}
+	}
+
+	return ""
+}
+
+// SignalEndpoint returns OTEL_EXPORTER_OTLP_<SIGNAL>_ENDPOINT, or
+// OTEL_EXPORTER_OTLP_ENDPOINT with base reporting that the generic variable
+// was used. signal is TRACES, METRICS or LOGS.
+func SignalEndpoint(signal string) (endpoint string, base bool) {
+	if v := os.Getenv("OTEL_EXPORTER_OTLP_" + signal + "_ENDPOINT"); v != "" {
+		return v, false
+	}
+
+	return os.Getenv("OTEL_EXPORTER_OTLP_ENDPOINT"), true
+}
+
+// MergeEnvHeaders adds the headers of OTEL_EXPORTER_OTLP_<SIGNAL>_HEADERS
+// and OTEL_EXPORTER_OTLP_HEADERS to headers, in that order of precedence.
+// Keys already in headers are kept.
+func MergeEnvHeaders(headers map[string]string, signal string) (map[string]string, error) {
+	for _, name := range []string{"OTEL_EXPORTER_OTLP_" + signal + "_HEADERS", "OTEL_EXPORTER_OTLP_HEADERS"} {
+		values, err := ParseKeyValues(os.Getenv(name))
+		if err != nil {
+			return nil, fmt.Errorf("invalid %s: %w", name, err)
+		}
+		for k, v := range values {
+			if _, ok := headers[k]; ok {
+				continue
+			}
+			if headers == nil {
+				headers = make(map[string]string)
+			}
+			headers[k] = v
+		}
+	}
+
+	return headers, nil
+}
+
+// ParseKeyValues parses the comma separated key=value lists used by the
+// OTEL_*_HEADERS and OTEL_RESOURCE_ATTRIBUTES variables. Values may be URL
+// encoded.
+func ParseKeyValues(s string) (map[string]string, error) {
+	if strings.TrimSpace(s) == "" {
+		return nil, nil
+	}
+
+	values := make(map[string]string)
+	for _, pair := range strings.Split(s, ",") {
+		if strings.TrimSpace(pair) == "" {
+			continue
+		}
+
+		k, v, ok := strings.Cut(pair, "=")
+		k = strings.TrimSpace(k)
+		if !ok || k == "" {
+			return nil, fmt.Errorf("malformed pair %q", pair)
+		}
+
+		unescaped, err := url.PathUnescape(strings.TrimSpace(v))
+		if err != nil {
+			return nil, fmt.Errorf("malformed value for %q: %w", k, err)
+		}
+		values[k] = unescaped
+	}
+
+	return values, nil
+}
+
+// Headers copies headers and, when token is set and the headers carry no
+// Authorization header, adds a Bearer authorization header.
+func Headers(headers map[string]string, token string) map[string]string {
+	out := make(map[string]string, len(headers)+2)
+	maps.Copy(out, headers)
+
+	if token == "" {
+		return out
+	}
+	for k := range out {
+		if strings.EqualFold(k, "Authorization") {
+			return out
+		}
+	}
+	out["Authorization"] = fmt.Sprintf("Bearer %s", token)
+
+	return out
+}
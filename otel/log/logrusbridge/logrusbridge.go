@@ -0,0 +1,51 @@
+// Package logrusbridge correlates logrus entries with traces.
+package logrusbridge
+
+import (
+	"github.com/sirupsen/logrus"
+	"go.opentelemetry.io/otel/trace"
+)
+
+const (
+	TraceIDKey = "trace_id"
+	SpanIDKey  = "span_id"
+)
+
+var _ logrus.Hook = (*Hook)(nil)
+
+// Hook adds the trace_id and span_id of the span in the entry context to
+// every entry. Register it with logger.AddHook and log through
+// logger.WithContext(ctx).
+type Hook struct {
+	levels []logrus.Level
+}
+
+// NewHook returns a Hook firing for levels, or for all levels if none are
+// given.
+func NewHook(levels ...logrus.Level) *Hook {
+	if len(levels) == 0 {
+		levels = logrus.AllLevels
+	}
+
+	return &Hook{levels: levels}
+}
+
+func (h *Hook) Levels() []logrus.Level {
+	return h.levels
+}
+
+func (h *Hook) Fire(entry *logrus.Entry) error {
+	if entry.Context == nil {
+		return nil
+	}
+
+	sc := trace.SpanContextFromContext(entry.Context)
+	if !sc.IsValid() {
+		return nil
+	}
+
+	entry.Data[TraceIDKey] = sc.TraceID().String()
+	entry.Data[SpanIDKey] = sc.SpanID().String()
+
+	return nil
+}
@@ -0,0 +1,55 @@
+// Package slogbridge correlates log/slog records with traces.
+package slogbridge
+
+import (
+	"context"
+	"log/slog"
+
+	"go.opentelemetry.io/otel/trace"
+)
+
+const (
+	TraceIDKey = "trace_id"
+	SpanIDKey  = "span_id"
+)
+
+var _ slog.Handler = (*Handler)(nil)
+
+// Handler adds the trace_id and span_id of the span in the context of each
+// record before passing it to the inner handler. Records logged without a
+// context, or outside a span, are passed on unchanged. Use the context
+// variants of the slog functions, e.g. slog.InfoContext, to pass the span.
+type Handler struct {
+	inner slog.Handler
+}
+
+// NewHandler wraps inner so that records carry the IDs of the current span.
+func NewHandler(inner slog.Handler) *Handler {
+	return &Handler{inner: inner}
+}
+
+func (h *Handler) Enabled(ctx context.Context, level slog.Level) bool {
+	return h.inner.Enabled(ctx, level)
+}
+
+func (h *Handler) Handle(ctx context.Context, r slog.Record) error {
+	if sc := trace.SpanContextFromContext(ctx); sc.IsValid() {
+		r = r.Clone()
+		r.AddAttrs(
+			slog.String(TraceIDKey, sc.TraceID().String()),
+			slog.String(SpanIDKey, sc.SpanID().String()),
+		)
+	}
+
+	return h.inner.Handle(ctx, r)
+}
+
+func (h *Handler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	return &Handler{inner: h.inner.WithAttrs(attrs)}
+}
+
+// WithGroup returns a Handler whose records, including the trace and span
+// IDs, are qualified by name.
+func (h *Handler) WithGroup(name string) slog.Handler {
+	return &Handler{inner: h.inner.WithGroup(name)}
+}
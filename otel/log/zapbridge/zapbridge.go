@@ -0,0 +1,88 @@
+// Package zapbridge correlates zap log entries with traces.
+package zapbridge
+
+import (
+	"context"
+
+	"go.opentelemetry.io/otel/trace"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+const (
+	TraceIDKey = "trace_id"
+	SpanIDKey  = "span_id"
+)
+
+// contextKey names the fields created by Context. They are of SkipType, so
+// encoders ignore them when the Core is not installed.
+const contextKey = "otel.context"
+
+// Context returns a field carrying ctx. A Core replaces it with the trace_id
+// and span_id of the span in ctx:
+//
+//	logger.Info("charged card", zapbridge.Context(ctx))
+func Context(ctx context.Context) zap.Field {
+	return zap.Field{Key: contextKey, Type: zapcore.SkipType, Interface: ctx}
+}
+
+var _ zapcore.Core = (*Core)(nil)
+
+// Core wraps a zapcore.Core and expands Context fields into trace and span
+// IDs. Install it with zap.WrapCore(zapbridge.NewCore).
+type Core struct {
+	zapcore.Core
+}
+
+// NewCore wraps inner.
+func NewCore(inner zapcore.Core) zapcore.Core {
+	return &Core{Core: inner}
+}
+
+func (c *Core) With(fields []zapcore.Field) zapcore.Core {
+	return &Core{Core: c.Core.With(expand(fields))}
+}
+
+func (c *Core) Check(e zapcore.Entry, ce *zapcore.CheckedEntry) *zapcore.CheckedEntry {
+	if c.Enabled(e.Level) {
+		return ce.AddCore(e, c)
+	}
+
+	return ce
+}
+
+func (c *Core) Write(e zapcore.Entry, fields []zapcore.Field) error {
+	return c.Core.Write(e, expand(fields))
+}
+
+// expand appends the trace and span IDs of each Context field with a valid
+// span context. fields itself is never modified.
+func expand(fields []zapcore.Field) []zapcore.Field {
+	var expanded []zapcore.Field
+	for _, f := range fields {
+		if f.Key != contextKey || f.Type != zapcore.SkipType {
+			continue
+		}
+		ctx, ok := f.Interface.(context.Context)
+		if !ok {
+			continue
+		}
+		sc := trace.SpanContextFromContext(ctx)
+		if !sc.IsValid() {
+			continue
+		}
+		if expanded == nil {
+			expanded = append(make([]zapcore.Field, 0, len(fields)+2), fields...)
+		}
+		expanded = append(expanded,
+			zap.String(TraceIDKey, sc.TraceID().String()),
+			zap.String(SpanIDKey, sc.SpanID().String()),
+		)
+	}
+
+	if expanded == nil {
+		return fields
+	}
+
+	return expanded
+}
@@ -0,0 +1,152 @@
+package logger
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"strings"
+
+	"go.opentelemetry.io/otel/exporters/otlp/otlplog/otlploggrpc"
+	"go.opentelemetry.io/otel/log"
+	"go.opentelemetry.io/otel/log/global"
+	"go.opentelemetry.io/otel/log/noop"
+	sdkLog "go.opentelemetry.io/otel/sdk/log"
+	"go.opentelemetry.io/otel/sdk/resource"
+	semconv "go.opentelemetry.io/otel/semconv/v1.9.0"
+	"google.golang.org/grpc/credentials"
+
+	"github.com/0x5w4/go-otel/otel/internal/otlpconfig"
+)
+
+var _ Logger = (*otelLogger)(nil)
+
+type Logger interface {
+	Logger() log.Logger
+	LoggerProvider() log.LoggerProvider
+	Shutdown(ctx context.Context) error
+}
+
+type otelLogger struct {
+	logger         log.Logger
+	loggerProvider log.LoggerProvider
+}
+
+type Config struct {
+	ExporterURL           string
+	SecretToken           string
+	ServiceName           string
+	ServiceVersion        string
+	DeploymentEnvironment string
+	Creds                 *credentials.TransportCredentials
+
+	// Headers are sent with every export. An Authorization header here
+	// takes precedence over the Bearer header derived from SecretToken.
+	Headers map[string]string
+}
+
+func InitLogger(ctx context.Context, cfg *Config) (*otelLogger, error) {
+	if cfg.ExporterURL == "" {
+		return nil, fmt.Errorf("endpoint is missing in the otlp logger configuration")
+	}
+
+	if cfg.ServiceName == "" {
+		return nil, fmt.Errorf("service name is missing in the otlp logger configuration")
+	}
+
+	endpoint, scheme := cfg.ExporterURL, ""
+	if strings.Contains(cfg.ExporterURL, "://") {
+		u, err := url.Parse(cfg.ExporterURL)
+		if err != nil {
+			return nil, fmt.Errorf("invalid exporter URL: %w", err)
+		}
+		endpoint, scheme = u.Host, u.Scheme
+	}
+
+	var secureOption otlploggrpc.Option
+	if cfg.Creds != nil && scheme != "http" {
+		secureOption = otlploggrpc.WithTLSCredentials(*cfg.Creds)
+	} else {
+		secureOption = otlploggrpc.WithInsecure()
+	}
+
+	exporter, err := otlploggrpc.New(
+		ctx,
+		otlploggrpc.WithEndpoint(endpoint),
+		secureOption,
+		otlploggrpc.WithHeaders(otlpconfig.Headers(cfg.Headers, cfg.SecretToken)),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create otlp log exporter: %w", err)
+	}
+
+	res, err := resource.New(
+		ctx,
+		resource.WithAttributes(
+			semconv.ServiceNameKey.String(cfg.ServiceName),
+			semconv.ServiceVersionKey.String(cfg.ServiceVersion),
+			semconv.DeploymentEnvironmentKey.String(cfg.DeploymentEnvironment),
+			semconv.TelemetrySDKLanguageKey.String("go"),
+		),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create otlp resource: %w", err)
+	}
+
+	lp := sdkLog.NewLoggerProvider(
+		sdkLog.WithProcessor(sdkLog.NewBatchProcessor(exporter)),
+		sdkLog.WithResource(res),
+	)
+	global.SetLoggerProvider(lp)
+
+	return &otelLogger{
+		logger:         global.Logger(fmt.Sprintf("%s-logger", cfg.ServiceName)),
+		loggerProvider: lp,
+	}, nil
+}
+
+func InitNoopLogger(ctx context.Context) (*otelLogger, error) {
+	lp := noop.NewLoggerProvider()
+	global.SetLoggerProvider(lp)
+
+	return &otelLogger{
+		logger:         global.Logger("noop-logger"),
+		loggerProvider: lp,
+	}, nil
+}
+
+func (l *otelLogger) Logger() log.Logger {
+	if l.logger != nil {
+		return l.logger
+	}
+
+	return nil
+}
+
+func (l *otelLogger) LoggerProvider() log.LoggerProvider {
+	if l.loggerProvider != nil {
+		return l.loggerProvider
+	}
+
+	return nil
+}
+
+// ForceFlush exports all pending telemetry.
+func (l *otelLogger) ForceFlush(ctx context.Context) error {
+	if lp, ok := l.loggerProvider.(*sdkLog.LoggerProvider); ok {
+		if err := lp.ForceFlush(ctx); err != nil {
+			return fmt.Errorf("failed to flush logger provider: %w", err)
+		}
+	}
+
+	return nil
+}
+
+func (l *otelLogger) Shutdown(ctx context.Context) error {
+	if lp, ok := l.loggerProvider.(*sdkLog.LoggerProvider); ok {
+		if err := lp.Shutdown(ctx); err != nil {
+			return fmt.Errorf("failed to shutdown logger provider: %w", err)
+		}
+	}
+
+	return nil
+}
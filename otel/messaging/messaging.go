@@ -0,0 +1,128 @@
+// Package messaging propagates trace context through the headers of
+// messages, for brokers without dedicated instrumentation in this module.
+// SQS message attributes of type String can be handled as a
+// map[string]string built from and copied back to the attributes.
+package messaging
+
+import (
+	"context"
+	"reflect"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/propagation"
+)
+
+// Headers are the header shapes supported by InjectIntoHeaders and
+// ExtractFromHeaders, including named map types such as amqp.Table
+// (map[string]any) and nats.Header (map[string][]string). Values stored in
+// a map[string]any are strings; []byte values are read as well.
+type Headers interface {
+	~map[string]string | ~map[string][]byte | ~map[string][]string | ~map[string]any
+}
+
+var (
+	_ propagation.TextMapCarrier = BytesMapCarrier(nil)
+	_ propagation.TextMapCarrier = ValuesMapCarrier(nil)
+	_ propagation.TextMapCarrier = AnyMapCarrier(nil)
+)
+
+// InjectIntoHeaders writes the trace context of ctx into headers with the
+// global propagator. headers must not be nil.
+func InjectIntoHeaders[H Headers](ctx context.Context, headers H) {
+	otel.GetTextMapPropagator().Inject(ctx, Carrier(headers))
+}
+
+// ExtractFromHeaders returns a copy of ctx carrying the trace context read
+// from headers with the global propagator.
+func ExtractFromHeaders[H Headers](ctx context.Context, headers H) context.Context {
+	return otel.GetTextMapPropagator().Extract(ctx, Carrier(headers))
+}
+
+// Carrier adapts headers to a propagation.TextMapCarrier. Keys are used as
+// they are, without canonicalization.
+func Carrier[H Headers](headers H) propagation.TextMapCarrier {
+	// Named map types cannot be matched by a type switch, so headers are
+	// converted to their underlying type, which shares the same map.
+	v := reflect.ValueOf(headers)
+	switch v.Type().Elem().Kind() {
+	case reflect.String:
+		return propagation.MapCarrier(v.Convert(reflect.TypeFor[map[string]string]()).Interface().(map[string]string))
+	case reflect.Interface:
+		return AnyMapCarrier(v.Convert(reflect.TypeFor[map[string]any]()).Interface().(map[string]any))
+	default:
+		if v.Type().Elem().Elem().Kind() == reflect.String {
+			return ValuesMapCarrier(v.Convert(reflect.TypeFor[map[string][]string]()).Interface().(map[string][]string))
+		}
+		return BytesMapCarrier(v.Convert(reflect.TypeFor[map[string][]byte]()).Interface().(map[string][]byte))
+	}
+}
+
+// BytesMapCarrier adapts headers with binary values, as used by Kafka and
+// Pulsar clients.
+type BytesMapCarrier map[string][]byte
+
+func (c BytesMapCarrier) Get(key string) string {
+	return string(c[key])
+}
+
+func (c BytesMapCarrier) Set(key, value string) {
+	c[key] = []byte(value)
+}
+
+func (c BytesMapCarrier) Keys() []string {
+	keys := make([]string, 0, len(c))
+	for k := range c {
+		keys = append(keys, k)
+	}
+	return keys
+}
+
+// ValuesMapCarrier adapts multi-value headers such as nats.Header. Unlike
+// propagation.HeaderCarrier it does not canonicalize keys.
+type ValuesMapCarrier map[string][]string
+
+func (c ValuesMapCarrier) Get(key string) string {
+	if values := c[key]; len(values) > 0 {
+		return values[0]
+	}
+	return ""
+}
+
+func (c ValuesMapCarrier) Set(key, value string) {
+	c[key] = []string{value}
+}
+
+func (c ValuesMapCarrier) Keys() []string {
+	keys := make([]string, 0, len(c))
+	for k := range c {
+		keys = append(keys, k)
+	}
+	return keys
+}
+
+// AnyMapCarrier adapts headers with arbitrary values such as amqp.Table.
+// Values are written as strings and read from strings or []byte.
+type AnyMapCarrier map[string]any
+
+func (c AnyMapCarrier) Get(key string) string {
+	switch v := c[key].(type) {
+	case string:
+		return v
+	case []byte:
+		return string(v)
+	default:
+		return ""
+	}
+}
+
+func (c AnyMapCarrier) Set(key, value string) {
+	c[key] = value
+}
+
+func (c AnyMapCarrier) Keys() []string {
+	keys := make([]string, 0, len(c))
+	for k := range c {
+		keys = append(keys, k)
+	}
+	return keys
+}
@@ -0,0 +1,34 @@
+package meter
+
+import (
+	"os"
+
+	"github.com/0x5w4/go-otel/otel/internal/otlpconfig"
+)
+
+// MergeEnv fills the fields of c that are still unset from the standard
+// OTEL_* environment variables, so the metrics can be sent to a different
+// collector than the traces. It reads:
+//
+//   - OTEL_EXPORTER_OTLP_METRICS_ENDPOINT, OTEL_EXPORTER_OTLP_ENDPOINT
+//   - OTEL_EXPORTER_OTLP_METRICS_HEADERS, OTEL_EXPORTER_OTLP_HEADERS
+//   - OTEL_SERVICE_NAME
+//
+// Headers are merged key by key.
+func (c *Config) MergeEnv() error {
+	if c.ExporterURL == "" {
+		c.ExporterURL, _ = otlpconfig.SignalEndpoint("METRICS")
+	}
+
+	if c.ServiceName == "" {
+		c.ServiceName = os.Getenv("OTEL_SERVICE_NAME")
+	}
+
+	headers, err := otlpconfig.MergeEnvHeaders(c.Headers, "METRICS")
+	if err != nil {
+		return err
+	}
+	c.Headers = headers
+
+	return nil
+}
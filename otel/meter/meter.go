@@ -0,0 +1,266 @@
+package meter
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/exporters/otlp/otlpmetric/otlpmetricgrpc"
+	otelPrometheus "go.opentelemetry.io/otel/exporters/prometheus"
+	"go.opentelemetry.io/otel/metric"
+	"go.opentelemetry.io/otel/metric/noop"
+	sdkMetric "go.opentelemetry.io/otel/sdk/metric"
+	"go.opentelemetry.io/otel/sdk/metric/exemplar"
+	"go.opentelemetry.io/otel/sdk/resource"
+	semconv "go.opentelemetry.io/otel/semconv/v1.9.0"
+	"google.golang.org/grpc/credentials"
+
+	hostotel "github.com/0x5w4/go-otel/otel/instrument/host"
+	runtimeotel "github.com/0x5w4/go-otel/otel/instrument/runtime"
+	"github.com/0x5w4/go-otel/otel/internal/otlpconfig"
+)
+
+var _ Meter = (*otelMeter)(nil)
+
+type Meter interface {
+	Meter() metric.Meter
+	MeterProvider() metric.MeterProvider
+	Shutdown(ctx context.Context) error
+}
+
+type otelMeter struct {
+	meter         metric.Meter
+	meterProvider metric.MeterProvider
+	promHandler   http.Handler
+}
+
+type Config struct {
+	ExporterURL           string
+	SecretToken           string
+	ServiceName           string
+	ServiceVersion        string
+	DeploymentEnvironment string
+	Creds                 *credentials.TransportCredentials
+
+	// Interval between two collections, 60s by default.
+	Interval time.Duration
+	// Timeout of a single export, 30s by default.
+	Timeout time.Duration
+
+	// RuntimeMetrics records Go runtime metrics (goroutines, memory, GC
+	// and scheduler latency) with the meter provider.
+	RuntimeMetrics bool
+
+	// HostMetrics records CPU, memory, network and disk metrics of the
+	// machine and the process with the meter provider.
+	HostMetrics bool
+
+	// Prometheus adds a pull exporter served by PrometheusHandler, next to
+	// the OTLP exporter. ExporterURL may be left empty to only serve
+	// Prometheus scrapes.
+	Prometheus bool
+
+	// Views customize the metric streams, e.g. histogram buckets per
+	// instrument, attribute filtering or renaming. ViewConfigs declare the
+	// same as plain structs and are applied after Views.
+	Views       []sdkMetric.View
+	ViewConfigs []ViewConfig
+
+	// ExemplarFilter selects the measurements kept as exemplars linking
+	// metric points to traces. The default, exemplar.SampledFilter, keeps
+	// measurements recorded with a context carrying a sampled span, e.g.
+	// one from a tracer of this module, so histogram buckets link to a
+	// representative trace. Use exemplar.AlwaysOffFilter to disable them.
+	ExemplarFilter exemplar.Filter
+
+	// Headers are sent with every export. An Authorization header here
+	// takes precedence over the Bearer header derived from SecretToken.
+	Headers map[string]string
+}
+
+func InitMeter(ctx context.Context, cfg *Config) (*otelMeter, error) {
+	if cfg.ExporterURL == "" && !cfg.Prometheus {
+		return nil, fmt.Errorf("endpoint is missing in the otlp meter configuration")
+	}
+
+	if cfg.ServiceName == "" {
+		return nil, fmt.Errorf("service name is missing in the otlp meter configuration")
+	}
+
+	res, err := resource.New(
+		ctx,
+		resource.WithAttributes(
+			semconv.ServiceNameKey.String(cfg.ServiceName),
+			semconv.ServiceVersionKey.String(cfg.ServiceVersion),
+			semconv.DeploymentEnvironmentKey.String(cfg.DeploymentEnvironment),
+			semconv.TelemetrySDKLanguageKey.String("go"),
+		),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create otlp resource: %w", err)
+	}
+
+	var producers []sdkMetric.Producer
+	if cfg.RuntimeMetrics {
+		producers = append(producers, runtimeotel.NewProducer())
+	}
+
+	providerOptions := []sdkMetric.Option{
+		sdkMetric.WithResource(res),
+		sdkMetric.WithView(views(cfg)...),
+	}
+	if cfg.ExemplarFilter != nil {
+		providerOptions = append(providerOptions, sdkMetric.WithExemplarFilter(cfg.ExemplarFilter))
+	}
+
+	if cfg.ExporterURL != "" {
+		reader, err := newOTLPReader(ctx, cfg, producers)
+		if err != nil {
+			return nil, err
+		}
+		providerOptions = append(providerOptions, sdkMetric.WithReader(reader))
+	}
+
+	var promHandler http.Handler
+	if cfg.Prometheus {
+		registry := prometheus.NewRegistry()
+		promOptions := []otelPrometheus.Option{otelPrometheus.WithRegisterer(registry)}
+		for _, p := range producers {
+			promOptions = append(promOptions, otelPrometheus.WithProducer(p))
+		}
+		reader, err := otelPrometheus.New(promOptions...)
+		if err != nil {
+			return nil, fmt.Errorf("failed to create prometheus exporter: %w", err)
+		}
+		providerOptions = append(providerOptions, sdkMetric.WithReader(reader))
+		// Exemplars are only part of the OpenMetrics exposition format.
+		promHandler = promhttp.HandlerFor(registry, promhttp.HandlerOpts{EnableOpenMetrics: true})
+	}
+
+	mp := sdkMetric.NewMeterProvider(providerOptions...)
+	otel.SetMeterProvider(mp)
+
+	if cfg.RuntimeMetrics {
+		if err := runtimeotel.Start(runtimeotel.WithMeterProvider(mp)); err != nil {
+			return nil, err
+		}
+	}
+	if cfg.HostMetrics {
+		if err := hostotel.Start(hostotel.WithMeterProvider(mp)); err != nil {
+			return nil, err
+		}
+	}
+
+	return &otelMeter{
+		meter:         otel.Meter(fmt.Sprintf("%s-meter", cfg.ServiceName)),
+		meterProvider: mp,
+		promHandler:   promHandler,
+	}, nil
+}
+
+// newOTLPReader returns a periodic reader pushing to the OTLP/gRPC endpoint
+// of cfg.
+func newOTLPReader(ctx context.Context, cfg *Config, producers []sdkMetric.Producer) (sdkMetric.Reader, error) {
+	endpoint, scheme := cfg.ExporterURL, ""
+	if strings.Contains(cfg.ExporterURL, "://") {
+		u, err := url.Parse(cfg.ExporterURL)
+		if err != nil {
+			return nil, fmt.Errorf("invalid exporter URL: %w", err)
+		}
+		endpoint, scheme = u.Host, u.Scheme
+	}
+
+	var secureOption otlpmetricgrpc.Option
+	if cfg.Creds != nil && scheme != "http" {
+		secureOption = otlpmetricgrpc.WithTLSCredentials(*cfg.Creds)
+	} else {
+		secureOption = otlpmetricgrpc.WithInsecure()
+	}
+
+	exporter, err := otlpmetricgrpc.New(
+		ctx,
+		otlpmetricgrpc.WithEndpoint(endpoint),
+		secureOption,
+		otlpmetricgrpc.WithHeaders(otlpconfig.Headers(cfg.Headers, cfg.SecretToken)),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create otlp metric exporter: %w", err)
+	}
+
+	var readerOptions []sdkMetric.PeriodicReaderOption
+	if cfg.Interval > 0 {
+		readerOptions = append(readerOptions, sdkMetric.WithInterval(cfg.Interval))
+	}
+	if cfg.Timeout > 0 {
+		readerOptions = append(readerOptions, sdkMetric.WithTimeout(cfg.Timeout))
+	}
+	for _, p := range producers {
+		readerOptions = append(readerOptions, sdkMetric.WithProducer(p))
+	}
+
+	return sdkMetric.NewPeriodicReader(exporter, readerOptions...), nil
+}
+
+func InitNoopMeter(ctx context.Context) (*otelMeter, error) {
+	mp := noop.NewMeterProvider()
+	otel.SetMeterProvider(mp)
+
+	return &otelMeter{
+		meter:         otel.Meter("noop-meter"),
+		meterProvider: mp,
+	}, nil
+}
+
+func (m *otelMeter) Meter() metric.Meter {
+	if m.meter != nil {
+		return m.meter
+	}
+
+	return nil
+}
+
+func (m *otelMeter) MeterProvider() metric.MeterProvider {
+	if m.meterProvider != nil {
+		return m.meterProvider
+	}
+
+	return nil
+}
+
+// PrometheusHandler returns the handler serving the metrics in the
+// Prometheus exposition format, to be mounted at /metrics. It responds with
+// 404 unless Config.Prometheus is set.
+func (m *otelMeter) PrometheusHandler() http.Handler {
+	if m.promHandler != nil {
+		return m.promHandler
+	}
+
+	return http.NotFoundHandler()
+}
+
+// ForceFlush exports all pending telemetry.
+func (m *otelMeter) ForceFlush(ctx context.Context) error {
+	if mp, ok := m.meterProvider.(*sdkMetric.MeterProvider); ok {
+		if err := mp.ForceFlush(ctx); err != nil {
+			return fmt.Errorf("failed to flush meter provider: %w", err)
+		}
+	}
+
+	return nil
+}
+
+func (m *otelMeter) Shutdown(ctx context.Context) error {
+	if mp, ok := m.meterProvider.(*sdkMetric.MeterProvider); ok {
+		if err := mp.Shutdown(ctx); err != nil {
+			return fmt.Errorf("failed to shutdown meter provider: %w", err)
+		}
+	}
+
+	return nil
+}
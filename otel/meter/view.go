@@ -0,0 +1,47 @@
+package meter
+
+import (
+	"go.opentelemetry.io/otel/attribute"
+	sdkMetric "go.opentelemetry.io/otel/sdk/metric"
+)
+
+// ViewConfig declares a view without constructing sdkMetric types. Unset
+// fields keep the defaults of the instrument.
+type ViewConfig struct {
+	// Instrument selects the instruments by name. * and ? wildcards are
+	// supported.
+	Instrument string
+	// Rename sets the name of the resulting metric stream. It is only valid
+	// when Instrument selects a single instrument.
+	Rename string
+	// Buckets sets explicit histogram bucket boundaries.
+	Buckets []float64
+	// AttributeKeys keeps only these attribute keys on the measurements.
+	AttributeKeys []string
+}
+
+func (v ViewConfig) view() sdkMetric.View {
+	stream := sdkMetric.Stream{Name: v.Rename}
+	if v.Buckets != nil {
+		stream.Aggregation = sdkMetric.AggregationExplicitBucketHistogram{Boundaries: v.Buckets}
+	}
+	if v.AttributeKeys != nil {
+		keys := make([]attribute.Key, len(v.AttributeKeys))
+		for i, k := range v.AttributeKeys {
+			keys[i] = attribute.Key(k)
+		}
+		stream.AttributeFilter = attribute.NewAllowKeysFilter(keys...)
+	}
+
+	return sdkMetric.NewView(sdkMetric.Instrument{Name: v.Instrument}, stream)
+}
+
+func views(cfg *Config) []sdkMetric.View {
+	views := make([]sdkMetric.View, 0, len(cfg.Views)+len(cfg.ViewConfigs))
+	views = append(views, cfg.Views...)
+	for _, v := range cfg.ViewConfigs {
+		views = append(views, v.view())
+	}
+
+	return views
+}
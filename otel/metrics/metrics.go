@@ -0,0 +1,222 @@
+// Package metrics is a small facade over the OpenTelemetry metric API for
+// the common cases: counters, up-down counters, histograms, gauges and
+// timers, created on first use and cached by name.
+//
+//	metrics.Counter("orders_total").Add(ctx, 1, attribute.String("region", "eu"))
+//
+//	timer := metrics.Timer("db_query")
+//	rows, err := db.QueryContext(ctx, query)
+//	timer.Stop(ctx)
+//
+// The package level functions use the global MeterProvider, so they can be
+// called before it is set up; use NewRegistry for a specific provider.
+// Instrument options only apply when an instrument is first created.
+package metrics
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/metric"
+	"go.opentelemetry.io/otel/metric/noop"
+)
+
+const instrumentationName = "github.com/0x5w4/go-otel/otel/metrics"
+
+// Registry creates and caches the instruments of one meter.
+type Registry struct {
+	meter metric.Meter
+
+	mu             sync.Mutex
+	counters       map[string]*Int64Counter
+	upDownCounters map[string]*Int64UpDownCounter
+	histograms     map[string]*Float64Histogram
+	gauges         map[string]*Float64Gauge
+}
+
+// NewRegistry returns a registry creating instruments with mp.
+func NewRegistry(mp metric.MeterProvider) *Registry {
+	return &Registry{
+		meter:          mp.Meter(instrumentationName),
+		counters:       make(map[string]*Int64Counter),
+		upDownCounters: make(map[string]*Int64UpDownCounter),
+		histograms:     make(map[string]*Float64Histogram),
+		gauges:         make(map[string]*Float64Gauge),
+	}
+}
+
+var defaultRegistry = NewRegistry(otel.GetMeterProvider())
+
+// Counter returns the counter name of the global MeterProvider.
+func Counter(name string, opts ...metric.InstrumentOption) *Int64Counter {
+	return defaultRegistry.Counter(name, opts...)
+}
+
+// UpDownCounter returns the up-down counter name of the global
+// MeterProvider.
+func UpDownCounter(name string, opts ...metric.InstrumentOption) *Int64UpDownCounter {
+	return defaultRegistry.UpDownCounter(name, opts...)
+}
+
+// Histogram returns the histogram name of the global MeterProvider.
+func Histogram(name string, opts ...metric.InstrumentOption) *Float64Histogram {
+	return defaultRegistry.Histogram(name, opts...)
+}
+
+// Gauge returns the gauge name of the global MeterProvider.
+func Gauge(name string, opts ...metric.InstrumentOption) *Float64Gauge {
+	return defaultRegistry.Gauge(name, opts...)
+}
+
+// Timer starts a timer recording into the histogram name of the global
+// MeterProvider, in seconds.
+func Timer(name string, opts ...metric.InstrumentOption) *Stopwatch {
+	return defaultRegistry.Timer(name, opts...)
+}
+
+// Counter returns the counter name, creating it on first use. The errors
+// of invalid names are passed to otel.Handle and yield a counter that
+// records nothing.
+func (r *Registry) Counter(name string, opts ...metric.InstrumentOption) *Int64Counter {
+	return cached(r, r.counters, name, func() *Int64Counter {
+		c, err := r.meter.Int64Counter(name, options[metric.Int64CounterOption](opts)...)
+		if err != nil {
+			otel.Handle(err)
+			c, _ = noop.Meter{}.Int64Counter(name)
+		}
+		return &Int64Counter{counter: c}
+	})
+}
+
+// UpDownCounter returns the up-down counter name, creating it on first
+// use.
+func (r *Registry) UpDownCounter(name string, opts ...metric.InstrumentOption) *Int64UpDownCounter {
+	return cached(r, r.upDownCounters, name, func() *Int64UpDownCounter {
+		c, err := r.meter.Int64UpDownCounter(name, options[metric.Int64UpDownCounterOption](opts)...)
+		if err != nil {
+			otel.Handle(err)
+			c, _ = noop.Meter{}.Int64UpDownCounter(name)
+		}
+		return &Int64UpDownCounter{counter: c}
+	})
+}
+
+// Histogram returns the histogram name, creating it on first use.
+func (r *Registry) Histogram(name string, opts ...metric.InstrumentOption) *Float64Histogram {
+	return cached(r, r.histograms, name, func() *Float64Histogram {
+		h, err := r.meter.Float64Histogram(name, options[metric.Float64HistogramOption](opts)...)
+		if err != nil {
+			otel.Handle(err)
+			h, _ = noop.Meter{}.Float64Histogram(name)
+		}
+		return &Float64Histogram{histogram: h}
+	})
+}
+
+// Gauge returns the gauge name, creating it on first use.
+func (r *Registry) Gauge(name string, opts ...metric.InstrumentOption) *Float64Gauge {
+	return cached(r, r.gauges, name, func() *Float64Gauge {
+		g, err := r.meter.Float64Gauge(name, options[metric.Float64GaugeOption](opts)...)
+		if err != nil {
+			otel.Handle(err)
+			g, _ = noop.Meter{}.Float64Gauge(name)
+		}
+		return &Float64Gauge{gauge: g}
+	})
+}
+
+// Timer starts a timer recording into the histogram name, in seconds
+// unless opts set another unit.
+func (r *Registry) Timer(name string, opts ...metric.InstrumentOption) *Stopwatch {
+	opts = append([]metric.InstrumentOption{metric.WithUnit("s")}, opts...)
+
+	return &Stopwatch{histogram: r.Histogram(name, opts...), start: time.Now()}
+}
+
+func cached[T any](r *Registry, instruments map[string]*T, name string, create func() *T) *T {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if i, ok := instruments[name]; ok {
+		return i
+	}
+	i := create()
+	instruments[name] = i
+
+	return i
+}
+
+// Int64Counter is a monotonic counter.
+type Int64Counter struct {
+	counter metric.Int64Counter
+}
+
+// Add adds n, which must not be negative.
+func (c *Int64Counter) Add(ctx context.Context, n int64, attrs ...attribute.KeyValue) {
+	c.counter.Add(ctx, n, metric.WithAttributes(attrs...))
+}
+
+// Inc adds 1.
+func (c *Int64Counter) Inc(ctx context.Context, attrs ...attribute.KeyValue) {
+	c.Add(ctx, 1, attrs...)
+}
+
+// Int64UpDownCounter is a counter that can go down, e.g. for items in a
+// queue or open connections.
+type Int64UpDownCounter struct {
+	counter metric.Int64UpDownCounter
+}
+
+// Add adds n, which may be negative.
+func (c *Int64UpDownCounter) Add(ctx context.Context, n int64, attrs ...attribute.KeyValue) {
+	c.counter.Add(ctx, n, metric.WithAttributes(attrs...))
+}
+
+// Float64Histogram records a distribution of values.
+type Float64Histogram struct {
+	histogram metric.Float64Histogram
+}
+
+// Record records v.
+func (h *Float64Histogram) Record(ctx context.Context, v float64, attrs ...attribute.KeyValue) {
+	h.histogram.Record(ctx, v, metric.WithAttributes(attrs...))
+}
+
+// Float64Gauge records the current value of something, e.g. a temperature
+// or a cache size.
+type Float64Gauge struct {
+	gauge metric.Float64Gauge
+}
+
+// Record records v as the current value.
+func (g *Float64Gauge) Record(ctx context.Context, v float64, attrs ...attribute.KeyValue) {
+	g.gauge.Record(ctx, v, metric.WithAttributes(attrs...))
+}
+
+// Stopwatch is a running timer started by Timer.
+type Stopwatch struct {
+	histogram *Float64Histogram
+	start     time.Time
+}
+
+// Stop records the time elapsed since the timer started in seconds and
+// returns it. A timer can be stopped once.
+func (s *Stopwatch) Stop(ctx context.Context, attrs ...attribute.KeyValue) time.Duration {
+	elapsed := time.Since(s.start)
+	s.histogram.Record(ctx, elapsed.Seconds(), attrs...)
+
+	return elapsed
+}
+
+// options converts instrument options, which satisfy the option interface
+// of every instrument kind, to the options of one kind.
+func options[O any](opts []metric.InstrumentOption) []O {
+	converted := make([]O, len(opts))
+	for i, opt := range opts {
+		converted[i] = any(opt).(O)
+	}
+	return converted
+}
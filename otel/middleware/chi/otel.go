@@ -0,0 +1,30 @@
+package chiotel
+
+import (
+	"net/http"
+
+	"github.com/go-chi/chi/v5"
+
+	httpotel "github.com/0x5w4/go-otel/otel/middleware/http"
+)
+
+// Middleware returns a chi middleware that wraps the handler chain in an
+// httpotel.Handler naming spans after the route pattern matched by chi,
+// e.g. "GET /users/{id}". Register it with Router.Use.
+func Middleware(opts ...httpotel.Option) func(http.Handler) http.Handler {
+	opts = append([]httpotel.Option{httpotel.WithRouteFunc(route)}, opts...)
+
+	return func(next http.Handler) http.Handler {
+		return httpotel.NewHandler(next, opts...)
+	}
+}
+
+// route returns the pattern of the route chi matched, which is complete
+// once the handler chain has returned.
+func route(r *http.Request) string {
+	if rctx := chi.RouteContext(r.Context()); rctx != nil {
+		return rctx.RoutePattern()
+	}
+
+	return ""
+}
@@ -0,0 +1,48 @@
+package echootel
+
+import (
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+
+	httpotel "github.com/0x5w4/go-otel/otel/middleware/http"
+)
+
+// Middleware returns an echo middleware that starts a server span for every
+// request like httpotel.Handler, named after the route template of the
+// matched handler, e.g. "GET /users/:id". Errors returned by the handler
+// are recorded and handled by the HTTP error handler of echo here, so the
+// span carries the status code of the error response; they are not
+// returned to the middlewares in front of this one.
+func Middleware(opts ...httpotel.Option) echo.MiddlewareFunc {
+	h := httpotel.NewHandler(nil, opts...)
+
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			r, span := h.StartRequest(c.Request())
+			if span == nil {
+				return next(c)
+			}
+			c.SetRequest(r)
+
+			defer func() {
+				if v := recover(); v != nil {
+					span.RecordPanic(v)
+					span.End(c.Path(), http.StatusInternalServerError)
+					panic(v)
+				}
+			}()
+
+			err := next(c)
+			if err != nil {
+				span.Span().RecordError(err)
+				c.Error(err)
+			}
+			span.End(c.Path(), c.Response().Status)
+
+			// The error has been handled; returning it would run the
+			// HTTP error handler a second time.
+			return nil
+		}
+	}
+}
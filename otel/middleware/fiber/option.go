@@ -0,0 +1,48 @@
+package fiberotel
+
+import (
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/trace"
+
+	"github.com/0x5w4/go-otel/otel/tracer"
+)
+
+type Option func(m *middleware)
+
+// WithTracer returns an Option to create spans with the TracerProvider of
+// a tracer initialized by this module.
+func WithTracer(t tracer.Tracer) Option {
+	return func(m *middleware) {
+		if t != nil {
+			WithTracerProvider(t.TracerProvider())(m)
+		}
+	}
+}
+
+// WithTracerProvider returns an Option to use the TracerProvider when
+// creating a Tracer.
+func WithTracerProvider(tp trace.TracerProvider) Option {
+	return func(m *middleware) {
+		if tp != nil {
+			m.tracer = tp.Tracer(instrumentationName)
+		}
+	}
+}
+
+// WithPropagator configures the propagator used to extract the incoming
+// trace context instead of the global one.
+func WithPropagator(p propagation.TextMapPropagator) Option {
+	return func(m *middleware) {
+		m.propagator = p
+	}
+}
+
+// WithSkipPaths configures request paths, e.g. health checks and metrics
+// endpoints, that are served without a span.
+func WithSkipPaths(paths ...string) Option {
+	return func(m *middleware) {
+		for _, path := range paths {
+			m.skipPaths[path] = struct{}{}
+		}
+	}
+}
@@ -0,0 +1,138 @@
+package fiberotel
+
+import (
+	"net/http"
+	"strings"
+
+	"github.com/gofiber/fiber/v2"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/propagation"
+	semconv "go.opentelemetry.io/otel/semconv/v1.20.0"
+	"go.opentelemetry.io/otel/semconv/v1.20.0/httpconv"
+	"go.opentelemetry.io/otel/trace"
+
+	"github.com/0x5w4/go-otel/otel/tracer"
+)
+
+const instrumentationName = "github.com/0x5w4/go-otel/otel/middleware/fiber"
+
+type middleware struct {
+	tracer     trace.Tracer
+	propagator propagation.TextMapPropagator
+	skipPaths  map[string]struct{}
+}
+
+// Middleware returns a fiber middleware that starts a server span for every
+// request, with the same attributes as httpotel.Handler, named after the
+// route template of the matched handler, e.g. "GET /users/:id". Fiber
+// serves fasthttp requests, so the net/http middleware cannot be reused.
+// Errors returned by the handler are recorded and passed to the error
+// handler of the app, so the span carries the status code of the error
+// response. The span context is in c.UserContext().
+func Middleware(opts ...Option) fiber.Handler {
+	m := &middleware{skipPaths: make(map[string]struct{})}
+	for _, opt := range opts {
+		opt(m)
+	}
+	if m.tracer == nil {
+		m.tracer = otel.Tracer(instrumentationName)
+	}
+
+	return m.handle
+}
+
+func (m *middleware) handle(c *fiber.Ctx) error {
+	if _, ok := m.skipPaths[c.Path()]; ok {
+		return c.Next()
+	}
+
+	propagator := m.propagator
+	if propagator == nil {
+		propagator = otel.GetTextMapPropagator()
+	}
+	ctx := propagator.Extract(c.UserContext(), headerCarrier{c})
+
+	// Fiber strings point into buffers fasthttp reuses after the request,
+	// so everything kept on the span is copied.
+	method := strings.Clone(c.Method())
+	attrs := []attribute.KeyValue{
+		semconv.HTTPMethod(method),
+		semconv.HTTPScheme(strings.Clone(c.Protocol())),
+		semconv.NetHostName(strings.Clone(c.Hostname())),
+		semconv.HTTPTarget(string(c.Request().RequestURI())),
+		attribute.String("url.path", strings.Clone(c.Path())),
+	}
+	if ua := c.Get(fiber.HeaderUserAgent); ua != "" {
+		attrs = append(attrs, semconv.UserAgentOriginal(strings.Clone(ua)))
+	}
+	if n := c.Request().Header.ContentLength(); n > 0 {
+		attrs = append(attrs, semconv.HTTPRequestContentLength(n))
+	}
+	if ip := c.Context().RemoteIP(); ip != nil {
+		attrs = append(attrs, semconv.NetSockPeerAddr(ip.String()))
+	}
+
+	ctx, span := m.tracer.Start(ctx, method,
+		trace.WithSpanKind(trace.SpanKindServer),
+		trace.WithAttributes(attrs...),
+	)
+	defer span.End()
+
+	c.SetUserContext(ctx)
+	panicked := true
+	defer func() {
+		if panicked {
+			v := recover()
+			tracer.RecordPanic(ctx, v)
+			span.SetAttributes(semconv.HTTPStatusCode(http.StatusInternalServerError))
+			panic(v)
+		}
+	}()
+
+	err := c.Next()
+	panicked = false
+	if err != nil {
+		span.RecordError(err)
+		if handlerErr := c.App().Config().ErrorHandler(c, err); handlerErr != nil {
+			_ = c.SendStatus(http.StatusInternalServerError)
+		}
+	}
+
+	// The route is only known once the router has matched the request.
+	// Requests no route matched are left on the route of the middleware,
+	// "/", which is not reported for other paths.
+	if route := c.Route().Path; route != "" && (route != "/" || c.Path() == "/") {
+		span.SetName(method + " " + route)
+		span.SetAttributes(semconv.HTTPRoute(route))
+	}
+	status := c.Response().StatusCode()
+	span.SetAttributes(semconv.HTTPStatusCode(status))
+	span.SetStatus(httpconv.ServerStatus(status))
+
+	return nil
+}
+
+// headerCarrier adapts the request headers of a fiber context to a
+// propagation.TextMapCarrier for extraction.
+type headerCarrier struct {
+	c *fiber.Ctx
+}
+
+func (h headerCarrier) Get(key string) string {
+	return strings.Clone(h.c.Get(key))
+}
+
+func (h headerCarrier) Set(key, value string) {
+	h.c.Request().Header.Set(key, value)
+}
+
+func (h headerCarrier) Keys() []string {
+	headers := h.c.GetReqHeaders()
+	keys := make([]string, 0, len(headers))
+	for k := range headers {
+		keys = append(keys, k)
+	}
+
+	return keys
+}
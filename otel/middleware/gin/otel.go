@@ -0,0 +1,42 @@
+package ginotel
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	httpotel "github.com/0x5w4/go-otel/otel/middleware/http"
+)
+
+// Middleware returns a gin middleware that starts a server span for every
+// request like httpotel.Handler, named after the route template of the
+// matched handler, e.g. "GET /users/:id". Panics are recorded on the span
+// and propagated to the recovery middleware, which should be registered
+// before this one.
+func Middleware(opts ...httpotel.Option) gin.HandlerFunc {
+	h := httpotel.NewHandler(nil, opts...)
+
+	return func(c *gin.Context) {
+		r, span := h.StartRequest(c.Request)
+		if span == nil {
+			c.Next()
+			return
+		}
+		c.Request = r
+
+		defer func() {
+			if v := recover(); v != nil {
+				span.RecordPanic(v)
+				span.End(c.FullPath(), http.StatusInternalServerError)
+				panic(v)
+			}
+		}()
+
+		c.Next()
+
+		if len(c.Errors) > 0 {
+			span.Span().RecordError(c.Errors.Last())
+		}
+		span.End(c.FullPath(), c.Writer.Status())
+	}
+}
@@ -0,0 +1,46 @@
+// Package grpcotel provides gRPC server interceptors that record panics of
+// handlers on the span of the call, as started by a stats handler such as
+// otelgrpc.NewServerHandler.
+package grpcotel
+
+import (
+	"context"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/0x5w4/go-otel/otel/tracer"
+)
+
+// RecoveryUnaryServerInterceptor records a panic of the handler on the span
+// in the call context, with its stack trace and an Error status, and
+// answers the call with an Internal error instead of crashing the server.
+// The panic value is kept off the error, which is sent to the client.
+func RecoveryUnaryServerInterceptor() grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (resp any, err error) {
+		defer func() {
+			if v := recover(); v != nil {
+				tracer.RecordPanic(ctx, v)
+				err = status.Error(codes.Internal, "internal error")
+			}
+		}()
+
+		return handler(ctx, req)
+	}
+}
+
+// RecoveryStreamServerInterceptor is RecoveryUnaryServerInterceptor for
+// streaming calls.
+func RecoveryStreamServerInterceptor() grpc.StreamServerInterceptor {
+	return func(srv any, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) (err error) {
+		defer func() {
+			if v := recover(); v != nil {
+				tracer.RecordPanic(ss.Context(), v)
+				err = status.Error(codes.Internal, "internal error")
+			}
+		}()
+
+		return handler(srv, ss)
+	}
+}
@@ -0,0 +1,64 @@
+package httpotel
+
+import (
+	"net/http"
+
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/trace"
+)
+
+type Option func(h *Handler)
+
+// WithTracerProvider returns an Option to use the TracerProvider when
+// creating a Tracer.
+func WithTracerProvider(tp trace.TracerProvider) Option {
+	return func(h *Handler) {
+		if tp != nil {
+			h.tracer = tp.Tracer(instrumentationName)
+		}
+	}
+}
+
+// WithPropagator configures the propagator used to extract the incoming
+// trace context instead of the global one.
+func WithPropagator(p propagation.TextMapPropagator) Option {
+	return func(h *Handler) {
+		h.propagator = p
+	}
+}
+
+// WithSkipPaths configures request paths, e.g. health checks and metrics
+// endpoints, that are served without a span.
+func WithSkipPaths(paths ...string) Option {
+	return func(h *Handler) {
+		for _, path := range paths {
+			h.skipPaths[path] = struct{}{}
+		}
+	}
+}
+
+// WithServerName configures the primary server name reported in the
+// net.host.name attribute. The request Host is used by default.
+func WithServerName(name string) Option {
+	return func(h *Handler) {
+		h.serverName = name
+	}
+}
+
+// WithRouteFunc configures how the low-cardinality route of a request is
+// determined, for routers that do not populate http.Request.Pattern. It is
+// called after the wrapped handler returns.
+func WithRouteFunc(f func(r *http.Request) string) Option {
+	return func(h *Handler) {
+		h.routeFunc = f
+	}
+}
+
+// WithRecovery makes the handler answer panics of the wrapped handler with a
+// 500 response instead of letting them propagate. Panics are recorded on
+// the span either way; http.ErrAbortHandler is always propagated.
+func WithRecovery() Option {
+	return func(h *Handler) {
+		h.recovery = true
+	}
+}
@@ -0,0 +1,197 @@
+package httpotel
+
+import (
+	"context"
+	"net/http"
+	"strings"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/propagation"
+	semconv "go.opentelemetry.io/otel/semconv/v1.20.0"
+	"go.opentelemetry.io/otel/semconv/v1.20.0/httpconv"
+	"go.opentelemetry.io/otel/trace"
+
+	"github.com/0x5w4/go-otel/otel/tracer"
+)
+
+const instrumentationName = "github.com/0x5w4/go-otel/otel/middleware/http"
+
+// Handler starts a server span for every request it serves.
+type Handler struct {
+	next       http.Handler
+	tracer     trace.Tracer
+	propagator propagation.TextMapPropagator
+	serverName string
+	skipPaths  map[string]struct{}
+	routeFunc  func(r *http.Request) string
+	recovery   bool
+}
+
+var _ http.Handler = (*Handler)(nil)
+
+func NewHandler(next http.Handler, opts ...Option) *Handler {
+	h := &Handler{
+		next:      next,
+		skipPaths: make(map[string]struct{}),
+	}
+	for _, opt := range opts {
+		opt(h)
+	}
+	if h.tracer == nil {
+		h.tracer = otel.Tracer(instrumentationName)
+	}
+	if h.routeFunc == nil {
+		h.routeFunc = patternRoute
+	}
+	return h
+}
+
+func (h *Handler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	r, req := h.StartRequest(r)
+	if req == nil {
+		h.next.ServeHTTP(w, r)
+		return
+	}
+	defer req.span.End()
+
+	rw := &responseWriter{ResponseWriter: w, status: http.StatusOK}
+	h.serve(rw, r)
+
+	// The route is only known once the router has matched the request.
+	req.panicked = rw.panicked
+	req.finish(h.routeFunc(r), rw.status)
+}
+
+// StartRequest extracts the trace context of r and starts its server span,
+// for framework adapters that serve requests through their own handler
+// chain. It returns r with the span in its context and the span, or r and
+// nil for skipped paths. Call End once the request has been served.
+func (h *Handler) StartRequest(r *http.Request) (*http.Request, *RequestSpan) {
+	if _, ok := h.skipPaths[r.URL.Path]; ok {
+		return r, nil
+	}
+
+	propagator := h.propagator
+	if propagator == nil {
+		propagator = otel.GetTextMapPropagator()
+	}
+	ctx := propagator.Extract(r.Context(), propagation.HeaderCarrier(r.Header))
+
+	ctx, span := h.tracer.Start(ctx, r.Method,
+		trace.WithSpanKind(trace.SpanKindServer),
+		trace.WithAttributes(httpconv.ServerRequest(h.serverName, r)...),
+		// The path lets samplers tell requests apart before the route is
+		// known.
+		trace.WithAttributes(attribute.String("url.path", r.URL.Path)),
+	)
+
+	return r.WithContext(ctx), &RequestSpan{ctx: ctx, span: span, method: r.Method}
+}
+
+// RequestSpan is the server span of a request started by StartRequest.
+type RequestSpan struct {
+	ctx      context.Context
+	span     trace.Span
+	method   string
+	panicked bool
+}
+
+// Span returns the underlying span.
+func (s *RequestSpan) Span() trace.Span {
+	return s.span
+}
+
+// RecordPanic records a panic of the handler on the span, which keeps its
+// Error status when End is called.
+func (s *RequestSpan) RecordPanic(v any) {
+	tracer.RecordPanic(s.ctx, v)
+	s.panicked = true
+}
+
+// End names the span after the low-cardinality route template of the
+// request, unless route is empty, records the response status and ends the
+// span.
+func (s *RequestSpan) End(route string, status int) {
+	s.finish(route, status)
+	s.span.End()
+}
+
+func (s *RequestSpan) finish(route string, status int) {
+	if route != "" {
+		s.span.SetName(s.method + " " + route)
+		s.span.SetAttributes(semconv.HTTPRoute(route))
+	}
+	s.span.SetAttributes(semconv.HTTPStatusCode(status))
+	if !s.panicked {
+		s.span.SetStatus(httpconv.ServerStatus(status))
+	}
+}
+
+// patternRoute returns the path of the http.ServeMux pattern that matched
+// r, without the method and host the pattern may start with.
+func patternRoute(r *http.Request) string {
+	route := r.Pattern
+	if i := strings.IndexByte(route, ' '); i >= 0 {
+		route = route[i+1:]
+	}
+	if i := strings.IndexByte(route, '/'); i > 0 {
+		route = route[i:]
+	}
+
+	return route
+}
+
+// serve calls the wrapped handler, recording a panic on the request span
+// and turning it into a 500 response when recovery is enabled.
+func (h *Handler) serve(w *responseWriter, r *http.Request) {
+	defer func() {
+		v := recover()
+		if v == nil {
+			return
+		}
+		tracer.RecordPanic(r.Context(), v)
+		if !h.recovery || v == http.ErrAbortHandler {
+			panic(v)
+		}
+		w.panicked = true
+		if !w.wroteHeader {
+			w.WriteHeader(http.StatusInternalServerError)
+		}
+	}()
+
+	h.next.ServeHTTP(w, r)
+}
+
+// responseWriter records the status code written by the handler.
+type responseWriter struct {
+	http.ResponseWriter
+	status      int
+	wroteHeader bool
+	panicked    bool
+}
+
+func (w *responseWriter) WriteHeader(code int) {
+	if !w.wroteHeader {
+		w.status = code
+		w.wroteHeader = true
+	}
+	w.ResponseWriter.WriteHeader(code)
+}
+
+func (w *responseWriter) Write(b []byte) (int, error) {
+	w.wroteHeader = true
+	return w.ResponseWriter.Write(b)
+}
+
+func (w *responseWriter) Flush() {
+	if f, ok := w.ResponseWriter.(http.Flusher); ok {
+		w.wroteHeader = true
+		f.Flush()
+	}
+}
+
+// Unwrap lets http.ResponseController reach the underlying writer.
+func (w *responseWriter) Unwrap() http.ResponseWriter {
+	return w.ResponseWriter
+}
@@ -0,0 +1,239 @@
+// Package oteltest provides assertions on recorded spans, for use with
+// tracer.InitTestTracer or tracertest.FakeTracer:
+//
+//	oteltest.AssertSpan(t, tr.EndedSpans(),
+//		oteltest.WithName("GET /users"),
+//		oteltest.WithAttr("http.status_code", 200),
+//		oteltest.WithStatus(codes.Error),
+//	)
+package oteltest
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+	"testing"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	sdkTrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// Matcher is a single expectation on a span.
+type Matcher struct {
+	desc  string
+	match func(s sdkTrace.ReadOnlySpan) bool
+}
+
+func (m Matcher) String() string {
+	return m.desc
+}
+
+// WithName expects the span name to be name.
+func WithName(name string) Matcher {
+	return Matcher{
+		desc: fmt.Sprintf("name %q", name),
+		match: func(s sdkTrace.ReadOnlySpan) bool {
+			return s.Name() == name
+		},
+	}
+}
+
+// WithAttr expects the span to have the attribute key with value. Integer
+// and float values match regardless of their Go width, so 200 matches an
+// int64 attribute.
+func WithAttr(key string, value any) Matcher {
+	want := normalize(value)
+
+	return Matcher{
+		desc: fmt.Sprintf("attribute %s=%v", key, value),
+		match: func(s sdkTrace.ReadOnlySpan) bool {
+			for _, kv := range s.Attributes() {
+				if string(kv.Key) == key {
+					return reflect.DeepEqual(normalize(kv.Value.AsInterface()), want)
+				}
+			}
+			return false
+		},
+	}
+}
+
+// WithoutAttr expects the span not to have the attribute key.
+func WithoutAttr(key string) Matcher {
+	return Matcher{
+		desc: fmt.Sprintf("no attribute %s", key),
+		match: func(s sdkTrace.ReadOnlySpan) bool {
+			for _, kv := range s.Attributes() {
+				if string(kv.Key) == key {
+					return false
+				}
+			}
+			return true
+		},
+	}
+}
+
+// WithStatus expects the span status code to be code.
+func WithStatus(code codes.Code) Matcher {
+	return Matcher{
+		desc: fmt.Sprintf("status %s", code),
+		match: func(s sdkTrace.ReadOnlySpan) bool {
+			return s.Status().Code == code
+		},
+	}
+}
+
+// WithKind expects the span kind to be kind.
+func WithKind(kind trace.SpanKind) Matcher {
+	return Matcher{
+		desc: fmt.Sprintf("kind %s", kind),
+		match: func(s sdkTrace.ReadOnlySpan) bool {
+			return s.SpanKind() == kind
+		},
+	}
+}
+
+// WithEvent expects the span to have an event named name.
+func WithEvent(name string) Matcher {
+	return Matcher{
+		desc: fmt.Sprintf("event %q", name),
+		match: func(s sdkTrace.ReadOnlySpan) bool {
+			for _, e := range s.Events() {
+				if e.Name == name {
+					return true
+				}
+			}
+			return false
+		},
+	}
+}
+
+// WithParent expects the span to be a child of parent.
+func WithParent(parent sdkTrace.ReadOnlySpan) Matcher {
+	return Matcher{
+		desc: fmt.Sprintf("parent %s", parent.Name()),
+		match: func(s sdkTrace.ReadOnlySpan) bool {
+			return s.Parent().SpanID() == parent.SpanContext().SpanID()
+		},
+	}
+}
+
+// FindSpan returns the first span matching all matchers, or nil.
+func FindSpan(spans []sdkTrace.ReadOnlySpan, matchers ...Matcher) sdkTrace.ReadOnlySpan {
+	for _, s := range spans {
+		if matches(s, matchers) {
+			return s
+		}
+	}
+
+	return nil
+}
+
+// AssertSpan fails the test unless one of spans matches all matchers, and
+// returns the first match.
+func AssertSpan(t testing.TB, spans []sdkTrace.ReadOnlySpan, matchers ...Matcher) sdkTrace.ReadOnlySpan {
+	t.Helper()
+
+	s := FindSpan(spans, matchers...)
+	if s == nil {
+		t.Errorf("no span with %s among:\n%s", describe(matchers), dump(spans))
+	}
+
+	return s
+}
+
+// AssertNoSpan fails the test if any of spans matches all matchers.
+func AssertNoSpan(t testing.TB, spans []sdkTrace.ReadOnlySpan, matchers ...Matcher) {
+	t.Helper()
+
+	if s := FindSpan(spans, matchers...); s != nil {
+		t.Errorf("unexpected span with %s: %s", describe(matchers), s.Name())
+	}
+}
+
+// AssertSpanCount fails the test unless exactly n of spans match all
+// matchers.
+func AssertSpanCount(t testing.TB, spans []sdkTrace.ReadOnlySpan, n int, matchers ...Matcher) {
+	t.Helper()
+
+	var count int
+	for _, s := range spans {
+		if matches(s, matchers) {
+			count++
+		}
+	}
+	if count != n {
+		t.Errorf("got %d spans with %s, want %d", count, describe(matchers), n)
+	}
+}
+
+func matches(s sdkTrace.ReadOnlySpan, matchers []Matcher) bool {
+	for _, m := range matchers {
+		if !m.match(s) {
+			return false
+		}
+	}
+
+	return true
+}
+
+func describe(matchers []Matcher) string {
+	if len(matchers) == 0 {
+		return "any properties"
+	}
+
+	descs := make([]string, len(matchers))
+	for i, m := range matchers {
+		descs[i] = m.desc
+	}
+
+	return strings.Join(descs, ", ")
+}
+
+func dump(spans []sdkTrace.ReadOnlySpan) string {
+	if len(spans) == 0 {
+		return "  (no spans)"
+	}
+
+	var b strings.Builder
+	for _, s := range spans {
+		set := attribute.NewSet(s.Attributes()...)
+		fmt.Fprintf(&b, "  %s [%s] %s\n", s.Name(), s.Status().Code, set.Encoded(attribute.DefaultEncoder()))
+	}
+
+	return strings.TrimSuffix(b.String(), "\n")
+}
+
+// normalize widens numeric values so expectations match attribute values
+// regardless of the Go type they were written with.
+func normalize(v any) any {
+	switch v := v.(type) {
+	case int:
+		return int64(v)
+	case int8:
+		return int64(v)
+	case int16:
+		return int64(v)
+	case int32:
+		return int64(v)
+	case uint8:
+		return int64(v)
+	case uint16:
+		return int64(v)
+	case uint32:
+		return int64(v)
+	case float32:
+		return float64(v)
+	case []int:
+		values := make([]int64, len(v))
+		for i, n := range v {
+			values[i] = int64(n)
+		}
+		return values
+	case fmt.Stringer:
+		return v.String()
+	default:
+		return v
+	}
+}
@@ -0,0 +1,59 @@
+package resourcebuilder
+
+import (
+	"go.opentelemetry.io/otel/attribute"
+	semconv "go.opentelemetry.io/otel/semconv/v1.9.0"
+)
+
+// Builder assembles the standard set of resource attributes. Empty values
+// are skipped so callers can pass through whatever their environment
+// provides.
+type Builder struct {
+	attrs []attribute.KeyValue
+}
+
+func New() *Builder {
+	return new(Builder)
+}
+
+// Service sets service.name and service.version.
+func (b *Builder) Service(name, version string) *Builder {
+	return b.add(semconv.ServiceNameKey, name).add(semconv.ServiceVersionKey, version)
+}
+
+// Environment sets deployment.environment.
+func (b *Builder) Environment(env string) *Builder {
+	return b.add(semconv.DeploymentEnvironmentKey, env)
+}
+
+// Cloud sets cloud.provider and cloud.region.
+func (b *Builder) Cloud(provider, region string) *Builder {
+	return b.add(semconv.CloudProviderKey, provider).add(semconv.CloudRegionKey, region)
+}
+
+// K8s sets k8s.cluster.name, k8s.namespace.name and k8s.pod.name.
+func (b *Builder) K8s(cluster, namespace, pod string) *Builder {
+	return b.
+		add(semconv.K8SClusterNameKey, cluster).
+		add(semconv.K8SNamespaceNameKey, namespace).
+		add(semconv.K8SPodNameKey, pod)
+}
+
+// Attributes appends arbitrary attributes.
+func (b *Builder) Attributes(attrs ...attribute.KeyValue) *Builder {
+	b.attrs = append(b.attrs, attrs...)
+	return b
+}
+
+// Build returns the attributes collected so far, ready for
+// tracer.Config.Attributes.
+func (b *Builder) Build() []attribute.KeyValue {
+	return append([]attribute.KeyValue(nil), b.attrs...)
+}
+
+func (b *Builder) add(key attribute.Key, value string) *Builder {
+	if value != "" {
+		b.attrs = append(b.attrs, key.String(value))
+	}
+	return b
+}
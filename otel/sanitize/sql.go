@@ -0,0 +1,188 @@
+// Package sanitize removes sensitive values from data recorded on spans.
+package sanitize
+
+import (
+	"strings"
+)
+
+// Dialect selects the quoting rules SQL statements are read with.
+type Dialect int
+
+const (
+	// DialectMySQL reads text in double quotes as a string and a backslash
+	// in strings as an escape, as MySQL does by default. Only backticks
+	// quote identifiers. SQL uses it, since taking a string for an
+	// identifier would record its value.
+	DialectMySQL Dialect = iota
+	// DialectPostgreSQL reads text in double quotes as an identifier and a
+	// backslash as an ordinary character except in E'...' strings, as
+	// PostgreSQL does with standard_conforming_strings, its default.
+	DialectPostgreSQL
+)
+
+// SQL replaces the literal values of query, quoted strings and numbers,
+// with ? and strips comments, keeping the shape of the statement:
+//
+//	SELECT * FROM users WHERE email = 'a@b.c' AND id > 42
+//	SELECT * FROM users WHERE email = ? AND id > ?
+//
+// It reads query with the rules of DialectMySQL; use Dialect.SQL for
+// another dialect.
+func SQL(query string) string {
+	return DialectMySQL.SQL(query)
+}
+
+// SQL is the package function SQL with the quoting rules of d. Identifiers,
+// keywords, quoted identifiers and placeholders such as $1 or :name are
+// kept. Unterminated strings and comments are replaced up to the end of the
+// query.
+func (d Dialect) SQL(query string) string {
+	var b strings.Builder
+	b.Grow(len(query))
+
+	postgres := d == DialectPostgreSQL
+	for i := 0; i < len(query); {
+		c := query[i]
+		switch {
+		case c == '\'' || c == '"' && !postgres:
+			i = skipQuoted(query, i, c, !postgres)
+			b.WriteByte('?')
+		case c == '"' || c == '`':
+			end := skipQuoted(query, i, c, false)
+			b.WriteString(query[i:end])
+			i = end
+		case postgres && (c == 'E' || c == 'e') && isEscapeString(query, i):
+			i = skipQuoted(query, i+1, '\'', true)
+			b.WriteByte('?')
+		case c == '-' && strings.HasPrefix(query[i:], "--"):
+			i = skipLine(query, i)
+		case c == '/' && strings.HasPrefix(query[i:], "/*"):
+			if end := strings.Index(query[i+2:], "*/"); end >= 0 {
+				i += end + 4
+			} else {
+				i = len(query)
+			}
+			b.WriteByte(' ')
+		case c == '$' && isDollarQuote(query, i):
+			i = skipDollarQuoted(query, i)
+			b.WriteByte('?')
+		case isDigit(c) && (i == 0 || !isIdentByte(query[i-1])):
+			i = skipNumber(query, i)
+			b.WriteByte('?')
+		case isIdentByte(c):
+			start := i
+			for i < len(query) && isIdentByte(query[i]) {
+				i++
+			}
+			b.WriteString(query[start:i])
+		default:
+			b.WriteByte(c)
+			i++
+		}
+	}
+
+	return b.String()
+}
+
+// isEscapeString reports whether a PostgreSQL E'...' string starts at i.
+func isEscapeString(s string, i int) bool {
+	return i+1 < len(s) && s[i+1] == '\'' && (i == 0 || !isIdentByte(s[i-1]))
+}
+
+// skipQuoted returns the index after the quoted section starting at i,
+// treating a doubled quote, and with backslash a backslash-escaped one, as
+// part of it.
+func skipQuoted(s string, i int, quote byte, backslash bool) int {
+	for i++; i < len(s); i++ {
+		switch s[i] {
+		case '\\':
+			if backslash {
+				i++
+			}
+		case quote:
+			if i+1 < len(s) && s[i+1] == quote {
+				i++
+				continue
+			}
+			return i + 1
+		}
+	}
+
+	return len(s)
+}
+
+func skipLine(s string, i int) int {
+	if end := strings.IndexByte(s[i:], '\n'); end >= 0 {
+		return i + end
+	}
+
+	return len(s)
+}
+
+// isDollarQuote reports whether a PostgreSQL dollar-quoted string, $$ or
+// $tag$, starts at i. Positional parameters such as $1 are not one.
+func isDollarQuote(s string, i int) bool {
+	for j := i + 1; j < len(s); j++ {
+		switch {
+		case s[j] == '$':
+			return true
+		case isDigit(s[j]) && j == i+1, !isIdentByte(s[j]):
+			return false
+		}
+	}
+
+	return false
+}
+
+func skipDollarQuoted(s string, i int) int {
+	end := strings.IndexByte(s[i+1:], '$') + i + 2
+	tag := s[i:end]
+	if closing := strings.Index(s[end:], tag); closing >= 0 {
+		return end + closing + len(tag)
+	}
+
+	return len(s)
+}
+
+// skipNumber returns the index after the decimal, hexadecimal or
+// exponent-form number starting at i.
+func skipNumber(s string, i int) int {
+	if strings.HasPrefix(s[i:], "0x") || strings.HasPrefix(s[i:], "0X") {
+		i += 2
+		for i < len(s) && isHexDigit(s[i]) {
+			i++
+		}
+		return i
+	}
+
+	for i < len(s) && (isDigit(s[i]) || s[i] == '.') {
+		i++
+	}
+	if i < len(s) && (s[i] == 'e' || s[i] == 'E') {
+		j := i + 1
+		if j < len(s) && (s[j] == '+' || s[j] == '-') {
+			j++
+		}
+		if j < len(s) && isDigit(s[j]) {
+			for i = j; i < len(s) && isDigit(s[i]); i++ {
+			}
+		}
+	}
+
+	return i
+}
+
+func isDigit(c byte) bool {
+	return '0' <= c && c <= '9'
+}
+
+func isHexDigit(c byte) bool {
+	return isDigit(c) || 'a' <= c && c <= 'f' || 'A' <= c && c <= 'F'
+}
+
+// isIdentByte reports whether c can be part of an identifier or a
+// placeholder such as $1, :name or @p1.
+func isIdentByte(c byte) bool {
+	return c == '_' || c == '$' || c == ':' || c == '@' || isDigit(c) ||
+		'a' <= c && c <= 'z' || 'A' <= c && c <= 'Z' || c >= 0x80
+}
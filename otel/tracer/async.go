@@ -0,0 +1,47 @@
+package tracer
+
+import (
+	"context"
+
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// DetachedContext returns a context carrying the values of ctx, including
+// its span, baggage and tracer, that is never canceled and has no deadline,
+// for work that must outlive the request that started it.
+func DetachedContext(ctx context.Context) context.Context {
+	return context.WithoutCancel(ctx)
+}
+
+// Go runs fn in a new goroutine under a span named name that is a child of
+// the span in ctx. fn gets a detached context, so it is not canceled when
+// the request of ctx finishes first. Errors and panics of fn are recorded
+// on the span; panics still crash the program.
+func Go(ctx context.Context, name string, fn func(ctx context.Context) error, opts ...trace.SpanStartOption) {
+	ctx = DetachedContext(ctx)
+	go runSpan(ctx, name, fn, opts...)
+}
+
+// GoLinked is like Go but starts a new trace whose root span links to the
+// span in ctx, for fire-and-forget tasks that would otherwise stretch the
+// trace of a short request.
+func GoLinked(ctx context.Context, name string, fn func(ctx context.Context) error, opts ...trace.SpanStartOption) {
+	ctx = DetachedContext(ctx)
+	opts = append([]trace.SpanStartOption{trace.WithNewRoot()}, opts...)
+	if sc := trace.SpanContextFromContext(ctx); sc.IsValid() {
+		opts = append(opts, trace.WithLinks(LinkFromSpanContext(sc)))
+	}
+	go runSpan(ctx, name, fn, opts...)
+}
+
+func runSpan(ctx context.Context, name string, fn func(ctx context.Context) error, opts ...trace.SpanStartOption) {
+	ctx, span := StartSpan(ctx, name, opts...)
+	defer span.End()
+	defer RecoverAndRecord(ctx)
+
+	if err := fn(ctx); err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	}
+}
@@ -0,0 +1,107 @@
+package tracer
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync/atomic"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// defaultPrefixExempt lists the semantic convention namespaces that keep
+// their keys when Config.AttributeKeyPrefix is set.
+var defaultPrefixExempt = []string{
+	"client.", "cloud.", "code.", "db.", "deployment.", "enduser.", "error.",
+	"exception.", "faas.", "http.", "k8s.", "messaging.", "net.", "network.",
+	"otel.", "peer.", "rpc.", "server.", "service.", "telemetry.", "thread.",
+	"url.", "user_agent.",
+}
+
+type keyPrefix struct {
+	prefix string
+	exempt []string
+}
+
+var attributeKeyPrefix atomic.Pointer[keyPrefix]
+
+func setAttributeKeyPrefix(prefix string, exempt []string) {
+	if prefix == "" {
+		attributeKeyPrefix.Store(nil)
+		return
+	}
+
+	if exempt == nil {
+		exempt = defaultPrefixExempt
+	}
+	attributeKeyPrefix.Store(&keyPrefix{prefix: prefix, exempt: exempt})
+}
+
+func prefixedKey(key string) string {
+	p := attributeKeyPrefix.Load()
+	if p == nil || strings.HasPrefix(key, p.prefix) {
+		return key
+	}
+
+	for _, exempt := range p.exempt {
+		if strings.HasPrefix(key, exempt) {
+			return key
+		}
+	}
+
+	return p.prefix + key
+}
+
+// Attr builds an attribute from a Go value, prefixing the key with
+// Config.AttributeKeyPrefix. Values of unsupported types are recorded with
+// their fmt representation.
+func Attr(key string, value any) attribute.KeyValue {
+	key = prefixedKey(key)
+
+	switch v := value.(type) {
+	case string:
+		return attribute.String(key, v)
+	case bool:
+		return attribute.Bool(key, v)
+	case int:
+		return attribute.Int(key, v)
+	case int64:
+		return attribute.Int64(key, v)
+	case float64:
+		return attribute.Float64(key, v)
+	case []string:
+		return attribute.StringSlice(key, v)
+	case []bool:
+		return attribute.BoolSlice(key, v)
+	case []int:
+		return attribute.IntSlice(key, v)
+	case []int64:
+		return attribute.Int64Slice(key, v)
+	case []float64:
+		return attribute.Float64Slice(key, v)
+	case fmt.Stringer:
+		return attribute.Stringer(key, v)
+	default:
+		return attribute.String(key, fmt.Sprint(v))
+	}
+}
+
+// SetAttributes sets attrs on the span in ctx, prefixing their keys with
+// Config.AttributeKeyPrefix.
+func SetAttributes(ctx context.Context, attrs ...attribute.KeyValue) {
+	span := trace.SpanFromContext(ctx)
+	if !span.IsRecording() {
+		return
+	}
+
+	if attributeKeyPrefix.Load() != nil {
+		prefixed := make([]attribute.KeyValue, len(attrs))
+		for i, kv := range attrs {
+			prefixed[i] = attribute.KeyValue{Key: attribute.Key(prefixedKey(string(kv.Key))), Value: kv.Value}
+		}
+		attrs = prefixed
+	}
+
+	span.SetAttributes(attrs...)
+}
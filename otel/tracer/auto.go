@@ -0,0 +1,48 @@
+package tracer
+
+import (
+	"context"
+	"runtime"
+	"strings"
+	"sync"
+
+	"go.opentelemetry.io/otel/trace"
+)
+
+// callerNames caches span names by program counter.
+var callerNames sync.Map
+
+// StartAuto starts a span named after the calling function, as
+// package.Function or package.(*Type).Method, with the tracer selected by
+// TracerFromContext. The name follows renames and moves, unlike a string
+// literal.
+func StartAuto(ctx context.Context, opts ...trace.SpanStartOption) (context.Context, trace.Span) {
+	return StartSpan(ctx, callerName(2), opts...)
+}
+
+// callerName returns the short function name skip frames above its caller.
+func callerName(skip int) string {
+	var pcs [1]uintptr
+	if runtime.Callers(skip+1, pcs[:]) == 0 {
+		return "unknown"
+	}
+
+	pc := pcs[0]
+	if name, ok := callerNames.Load(pc); ok {
+		return name.(string)
+	}
+
+	frame, _ := runtime.CallersFrames(pcs[:]).Next()
+	name := frame.Function
+	if name == "" {
+		name = "unknown"
+	}
+	// Trim the import path but keep the package name.
+	if i := strings.LastIndexByte(name, '/'); i >= 0 {
+		name = name[i+1:]
+	}
+
+	callerNames.Store(pc, name)
+
+	return name
+}
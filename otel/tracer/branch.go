@@ -0,0 +1,84 @@
+package tracer
+
+import (
+	"context"
+	"errors"
+	"sync"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+)
+
+type branchConfig struct {
+	concurrency int
+}
+
+type BranchOption func(c *branchConfig)
+
+// WithConcurrency runs up to limit branches at the same time. A limit of
+// zero or less runs every branch concurrently.
+func WithConcurrency(limit int) BranchOption {
+	return func(c *branchConfig) {
+		c.concurrency = limit
+		if limit <= 0 {
+			c.concurrency = -1
+		}
+	}
+}
+
+// ForEachBranch calls fn n times, each call under its own child span of the
+// span in ctx. Branches run one after another unless WithConcurrency is
+// given. Every branch runs to completion; the returned error joins the
+// errors of all failed branches. An n of zero or less runs nothing.
+func ForEachBranch(ctx context.Context, name string, n int, fn func(ctx context.Context, i int) error, opts ...BranchOption) error {
+	if n <= 0 {
+		return nil
+	}
+
+	var cfg branchConfig
+	for _, opt := range opts {
+		opt(&cfg)
+	}
+
+	errs := make([]error, n)
+	run := func(i int) {
+		ctx, span := StartSpan(ctx, name, trace.WithAttributes(attribute.Int("branch.index", i)))
+		defer span.End()
+
+		if err := fn(ctx, i); err != nil {
+			span.RecordError(err)
+			span.SetStatus(codes.Error, err.Error())
+			errs[i] = err
+		}
+	}
+
+	if cfg.concurrency == 0 {
+		for i := 0; i < n; i++ {
+			run(i)
+		}
+		return errors.Join(errs...)
+	}
+
+	limit := cfg.concurrency
+	if limit < 0 || limit > n {
+		limit = n
+	}
+	sem := make(chan struct{}, limit)
+
+	var wg sync.WaitGroup
+	for i := 0; i < n; i++ {
+		sem <- struct{}{}
+		wg.Add(1)
+		go func(i int) {
+			defer func() {
+				<-sem
+				wg.Done()
+			}()
+			run(i)
+		}(i)
+	}
+	wg.Wait()
+
+	return errors.Join(errs...)
+}
@@ -0,0 +1,103 @@
+package tracer
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"sync"
+	"time"
+
+	sdkTrace "go.opentelemetry.io/otel/sdk/trace"
+)
+
+// ErrExportCircuitOpen is returned for batches dropped without an export
+// attempt while the circuit breaker of Config.CircuitBreakerThreshold is
+// open.
+var ErrExportCircuitOpen = errors.New("otlp export circuit open")
+
+const defaultCircuitBreakerCooldown = 30 * time.Second
+
+var _ sdkTrace.SpanExporter = (*breakerExporter)(nil)
+
+// breakerExporter stops calling its exporter after threshold consecutive
+// failed exports and drops batches at once instead, so a dead collector
+// does not hold every batch for the full retry time. Once cooldown has
+// passed, one export is let through as a probe: success closes the
+// circuit, failure keeps it open for another cooldown.
+type breakerExporter struct {
+	sdkTrace.SpanExporter
+	threshold int
+	cooldown  time.Duration
+	logger    *slog.Logger
+
+	mu       sync.Mutex
+	failures int
+	openedAt time.Time
+	probing  bool
+}
+
+func newBreakerExporter(next sdkTrace.SpanExporter, threshold int, cooldown time.Duration, logger *slog.Logger) *breakerExporter {
+	if cooldown <= 0 {
+		cooldown = defaultCircuitBreakerCooldown
+	}
+
+	return &breakerExporter{
+		SpanExporter: next,
+		threshold:    threshold,
+		cooldown:     cooldown,
+		logger:       logger,
+	}
+}
+
+func (e *breakerExporter) ExportSpans(ctx context.Context, spans []sdkTrace.ReadOnlySpan) error {
+	if !e.allow() {
+		return ErrExportCircuitOpen
+	}
+
+	err := e.SpanExporter.ExportSpans(ctx, spans)
+	e.record(err)
+
+	return err
+}
+
+// allow reports whether an export may be attempted, marking it as the probe
+// when the circuit is open and the cooldown has passed.
+func (e *breakerExporter) allow() bool {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	if e.openedAt.IsZero() {
+		return true
+	}
+	if e.probing || time.Since(e.openedAt) < e.cooldown {
+		return false
+	}
+	e.probing = true
+
+	return true
+}
+
+func (e *breakerExporter) record(err error) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	wasOpen := !e.openedAt.IsZero()
+	e.probing = false
+
+	if err == nil {
+		e.failures = 0
+		if wasOpen {
+			e.openedAt = time.Time{}
+			e.logger.Info("otlp export circuit closed")
+		}
+		return
+	}
+
+	e.failures++
+	if wasOpen || e.failures >= e.threshold {
+		if !wasOpen {
+			e.logger.Warn("otlp export circuit opened, dropping spans", "failures", e.failures, "cooldown", e.cooldown)
+		}
+		e.openedAt = time.Now()
+	}
+}
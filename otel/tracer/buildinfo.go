@@ -0,0 +1,62 @@
+package tracer
+
+import (
+	"path"
+	"runtime/debug"
+
+	"go.opentelemetry.io/otel/attribute"
+)
+
+// buildInfoVersion returns the main module version embedded by the Go
+// toolchain, falling back to the VCS revision for local builds whose
+// module version is reported as "(devel)".
+func buildInfoVersion() string {
+	info, ok := debug.ReadBuildInfo()
+	if !ok {
+		return ""
+	}
+
+	if v := info.Main.Version; v != "" && v != "(devel)" {
+		return v
+	}
+
+	for _, setting := range info.Settings {
+		if setting.Key == "vcs.revision" {
+			return setting.Value
+		}
+	}
+
+	return ""
+}
+
+// buildInfoModuleName returns the last element of the main module path,
+// e.g. "orders" for "github.com/acme/orders".
+func buildInfoModuleName() string {
+	info, ok := debug.ReadBuildInfo()
+	if !ok || info.Main.Path == "" {
+		return ""
+	}
+
+	return path.Base(info.Main.Path)
+}
+
+// buildInfoAttributes describes the running binary: the Go version and the
+// VCS stamp embedded at build time.
+func buildInfoAttributes() []attribute.KeyValue {
+	info, ok := debug.ReadBuildInfo()
+	if !ok {
+		return nil
+	}
+
+	attrs := []attribute.KeyValue{
+		attribute.String("go.version", info.GoVersion),
+	}
+	for _, setting := range info.Settings {
+		switch setting.Key {
+		case "vcs.revision", "vcs.time", "vcs.modified":
+			attrs = append(attrs, attribute.String(setting.Key, setting.Value))
+		}
+	}
+
+	return attrs
+}
@@ -0,0 +1,223 @@
+package tracer
+
+import (
+	"fmt"
+	"net"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracegrpc"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracehttp"
+	"google.golang.org/grpc"
+
+	"github.com/0x5w4/go-otel/otel/internal/otlpconfig"
+)
+
+const (
+	ProtocolGRPC         = "grpc"
+	ProtocolHTTPProtobuf = "http/protobuf"
+)
+
+const (
+	CompressionNone = "none"
+	CompressionGzip = "gzip"
+)
+
+// RetryConfig mirrors the retry settings shared by the OTLP gRPC and HTTP
+// exporters.
+type RetryConfig struct {
+	// Enabled turns retrying failed exports on.
+	Enabled bool
+	// InitialInterval is the wait after the first failure.
+	InitialInterval time.Duration
+	// MaxInterval caps the wait between attempts.
+	MaxInterval time.Duration
+	// MaxElapsedTime bounds the time spent retrying one batch before it is
+	// dropped.
+	MaxElapsedTime time.Duration
+}
+
+// NoRetry returns a RetryConfig that disables retries, so a failed export
+// returns at once.
+func NoRetry() *RetryConfig {
+	return &RetryConfig{Enabled: false}
+}
+
+type endpointURL struct {
+	host   string
+	scheme string
+	path   string
+}
+
+// schemeUnix marks exporter URLs such as unix:///var/run/otel/collector.sock
+// that reach a collector listening on a Unix domain socket.
+const schemeUnix = "unix"
+
+// parseEndpoint splits the exporter URL into the host:port endpoint expected
+// by the OTLP clients, its scheme and its path. A bare host:port, the usual
+// form for OTLP/gRPC, is accepted as is and reported with an empty scheme.
+// For unix URLs the path is the socket path and the host is empty.
+func parseEndpoint(raw string) (endpointURL, error) {
+	if !strings.Contains(raw, "://") {
+		if _, _, err := net.SplitHostPort(raw); err != nil {
+			return endpointURL{}, fmt.Errorf("invalid exporter endpoint: %w", err)
+		}
+		return endpointURL{host: raw}, nil
+	}
+
+	u, err := url.Parse(raw)
+	if err != nil {
+		return endpointURL{}, fmt.Errorf("invalid exporter URL: %w", err)
+	}
+
+	if u.Scheme == schemeUnix && u.Path == "" {
+		return endpointURL{}, fmt.Errorf("invalid exporter URL: missing unix socket path")
+	}
+
+	return endpointURL{host: u.Host, scheme: u.Scheme, path: u.Path}, nil
+}
+
+// grpcTarget returns the target the gRPC client dials, using the unix
+// resolver of gRPC for socket endpoints.
+func (e endpointURL) grpcTarget() string {
+	if e.scheme == schemeUnix {
+		return schemeUnix + "://" + e.path
+	}
+
+	return e.host
+}
+
+func newClient(cfg *Config) (otlptrace.Client, error) {
+	endpoint, err := parseEndpoint(cfg.ExporterURL)
+	if err != nil {
+		return nil, err
+	}
+
+	switch cfg.Compression {
+	case "", CompressionNone, CompressionGzip:
+	default:
+		return nil, fmt.Errorf("unsupported otlp compression %q", cfg.Compression)
+	}
+
+	switch cfg.Protocol {
+	case "", ProtocolGRPC:
+		return newGRPCClient(cfg, endpoint)
+	case ProtocolHTTPProtobuf:
+		if endpoint.scheme == schemeUnix {
+			return nil, fmt.Errorf("unix socket endpoints require the %s protocol", ProtocolGRPC)
+		}
+		return newHTTPClient(cfg, endpoint)
+	default:
+		return nil, fmt.Errorf("unsupported otlp protocol %q", cfg.Protocol)
+	}
+}
+
+func newGRPCClient(cfg *Config, endpoint endpointURL) (otlptrace.Client, error) {
+	creds, err := transportCredentials(cfg, endpoint.scheme)
+	if err != nil {
+		return nil, err
+	}
+
+	var secureOption otlptracegrpc.Option
+	if creds != nil {
+		secureOption = otlptracegrpc.WithTLSCredentials(creds)
+	} else {
+		secureOption = otlptracegrpc.WithInsecure()
+	}
+
+	clientOptions := []otlptracegrpc.Option{
+		otlptracegrpc.WithEndpoint(endpoint.grpcTarget()),
+		secureOption,
+	}
+
+	if tokens := tokenSource(cfg); tokens != nil || cfg.HeaderProvider != nil {
+		clientOptions = append(clientOptions, otlptracegrpc.WithDialOption(grpc.WithPerRPCCredentials(&exportCredentials{
+			tokens:           tokens,
+			headers:          cfg.HeaderProvider,
+			requireTransport: creds != nil,
+		})))
+	}
+
+	clientOptions = append(clientOptions, otlptracegrpc.WithHeaders(exporterHeaders(cfg)))
+
+	// gRPC owns the user-agent header, so it has to be set on the dial
+	// options rather than through the exporter headers.
+	if cfg.UserAgent != "" {
+		clientOptions = append(clientOptions, otlptracegrpc.WithDialOption(grpc.WithUserAgent(cfg.UserAgent)))
+	}
+
+	if cfg.GRPCServiceConfig != "" {
+		clientOptions = append(clientOptions, otlptracegrpc.WithDialOption(grpc.WithDefaultServiceConfig(cfg.GRPCServiceConfig)))
+	}
+
+	if cfg.Retry != nil {
+		clientOptions = append(clientOptions, otlptracegrpc.WithRetry(otlptracegrpc.RetryConfig(*cfg.Retry)))
+	}
+
+	if cfg.Compression == CompressionGzip {
+		clientOptions = append(clientOptions, otlptracegrpc.WithCompressor(CompressionGzip))
+	}
+
+	if len(cfg.GRPCDialOptions) > 0 {
+		clientOptions = append(clientOptions, otlptracegrpc.WithDialOption(cfg.GRPCDialOptions...))
+	}
+
+	return otlptracegrpc.NewClient(clientOptions...), nil
+}
+
+func newHTTPClient(cfg *Config, endpoint endpointURL) (otlptrace.Client, error) {
+	tlsConfig, err := exporterTLSConfig(cfg, endpoint.scheme)
+	if err != nil {
+		return nil, err
+	}
+
+	clientOptions := []otlptracehttp.Option{
+		otlptracehttp.WithEndpoint(endpoint.host),
+	}
+	if endpoint.path != "" && endpoint.path != "/" {
+		clientOptions = append(clientOptions, otlptracehttp.WithURLPath(endpoint.path))
+	}
+
+	headers := exporterHeaders(cfg)
+	if cfg.UserAgent != "" {
+		headers["User-Agent"] = cfg.UserAgent
+	}
+
+	if tokens := tokenSource(cfg); tokens != nil || cfg.HeaderProvider != nil {
+		// The HTTP exporter has no per-request credentials hook, so the
+		// token and dynamic headers are set by the transport of a
+		// dedicated client.
+		base := http.DefaultTransport.(*http.Transport).Clone()
+		base.TLSClientConfig = tlsConfig
+		clientOptions = append(clientOptions, otlptracehttp.WithHTTPClient(&http.Client{
+			Transport: &exportTransport{tokens: tokens, headers: cfg.HeaderProvider, base: base},
+		}))
+	}
+	clientOptions = append(clientOptions, otlptracehttp.WithHeaders(headers))
+
+	if cfg.Compression == CompressionGzip {
+		clientOptions = append(clientOptions, otlptracehttp.WithCompression(otlptracehttp.GzipCompression))
+	}
+
+	if tlsConfig != nil {
+		clientOptions = append(clientOptions, otlptracehttp.WithTLSClientConfig(tlsConfig))
+	} else {
+		clientOptions = append(clientOptions, otlptracehttp.WithInsecure())
+	}
+
+	return otlptracehttp.NewClient(clientOptions...), nil
+}
+
+// exporterHeaders copies Config.Headers and, when SecretToken is set and
+// neither a token source nor the headers provide one, adds a Bearer
+// authorization header.
+func exporterHeaders(cfg *Config) map[string]string {
+	if tokenSource(cfg) != nil {
+		return otlpconfig.Headers(cfg.Headers, "")
+	}
+
+	return otlpconfig.Headers(cfg.Headers, cfg.SecretToken)
+}
@@ -0,0 +1,125 @@
+package tracer
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"google.golang.org/grpc/credentials"
+)
+
+// TokenSource supplies the bearer token sent with every export request. It
+// is called on each export, so short-lived OAuth or OIDC tokens can be
+// refreshed without rebuilding the exporter; implementations should cache
+// the token until it nears expiry.
+type TokenSource interface {
+	Token(ctx context.Context) (string, error)
+}
+
+// TokenSourceFunc adapts a function to a TokenSource.
+type TokenSourceFunc func(ctx context.Context) (string, error)
+
+func (f TokenSourceFunc) Token(ctx context.Context) (string, error) {
+	return f(ctx)
+}
+
+// tokenSource returns Config.TokenSource, or Config.TokenProvider adapted to
+// a TokenSource, or nil if neither is set.
+func tokenSource(cfg *Config) TokenSource {
+	switch {
+	case cfg.TokenSource != nil:
+		return cfg.TokenSource
+	case cfg.TokenProvider != nil:
+		return TokenSourceFunc(func(context.Context) (string, error) {
+			return cfg.TokenProvider(), nil
+		})
+	default:
+		return nil
+	}
+}
+
+// HeaderProvider supplies headers added to every export request, e.g. the
+// X-Scope-OrgID of the tenant a multi-tenant collector routes by. It is
+// called on each export, so its headers can follow runtime state.
+type HeaderProvider interface {
+	Headers(ctx context.Context) (map[string]string, error)
+}
+
+// HeaderProviderFunc adapts a function to a HeaderProvider.
+type HeaderProviderFunc func(ctx context.Context) (map[string]string, error)
+
+func (f HeaderProviderFunc) Headers(ctx context.Context) (map[string]string, error) {
+	return f(ctx)
+}
+
+var _ credentials.PerRPCCredentials = (*exportCredentials)(nil)
+
+// exportCredentials attaches the headers of a HeaderProvider and a bearer
+// token to every export request. Both are resolved on each call so rotated
+// tokens are picked up without rebuilding the exporter.
+type exportCredentials struct {
+	tokens           TokenSource
+	headers          HeaderProvider
+	requireTransport bool
+}
+
+func (c *exportCredentials) GetRequestMetadata(ctx context.Context, uri ...string) (map[string]string, error) {
+	md := make(map[string]string)
+	if c.headers != nil {
+		headers, err := c.headers.Headers(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get otlp export headers: %w", err)
+		}
+		for k, v := range headers {
+			md[strings.ToLower(k)] = v
+		}
+	}
+
+	if c.tokens != nil {
+		token, err := c.tokens.Token(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get otlp export token: %w", err)
+		}
+		md["authorization"] = fmt.Sprintf("Bearer %s", token)
+	}
+
+	return md, nil
+}
+
+func (c *exportCredentials) RequireTransportSecurity() bool {
+	return c.requireTransport
+}
+
+var _ http.RoundTripper = (*exportTransport)(nil)
+
+// exportTransport is the HTTP counterpart of exportCredentials.
+type exportTransport struct {
+	tokens  TokenSource
+	headers HeaderProvider
+	base    http.RoundTripper
+}
+
+func (t *exportTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	req = req.Clone(req.Context())
+
+	if t.headers != nil {
+		headers, err := t.headers.Headers(req.Context())
+		if err != nil {
+			return nil, fmt.Errorf("failed to get otlp export headers: %w", err)
+		}
+		for k, v := range headers {
+			req.Header.Set(k, v)
+		}
+	}
+
+	if t.tokens != nil {
+		token, err := t.tokens.Token(req.Context())
+		if err != nil {
+			return nil, fmt.Errorf("failed to get otlp export token: %w", err)
+		}
+		req.Header.Set("Authorization", fmt.Sprintf("Bearer %s", token))
+	}
+
+	return t.base.RoundTrip(req)
+}
@@ -0,0 +1,130 @@
+package tracer
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"runtime"
+	"time"
+
+	"go.opentelemetry.io/contrib/detectors/gcp"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/sdk/resource"
+	semconv "go.opentelemetry.io/otel/semconv/v1.9.0"
+)
+
+// resourceDetectorOptions maps the detector names of
+// Config.ResourceDetectors to resource options.
+func resourceDetectorOptions(names []string) ([]resource.Option, error) {
+	options := make([]resource.Option, 0, len(names))
+	for _, name := range names {
+		switch name {
+		case "host":
+			options = append(options,
+				resource.WithHost(),
+				resource.WithOSType(),
+				resource.WithAttributes(semconv.HostArchKey.String(runtime.GOARCH)),
+			)
+		case "process":
+			options = append(options,
+				resource.WithProcessPID(),
+				resource.WithProcessExecutableName(),
+				resource.WithProcessRuntimeName(),
+				resource.WithProcessRuntimeVersion(),
+				resource.WithProcessRuntimeDescription(),
+			)
+		case "container":
+			options = append(options, resource.WithContainer())
+		case "k8s":
+			options = append(options, resource.WithAttributes(k8sEnvAttributes()...))
+		case "aws":
+			options = append(options, resource.WithDetectors(ec2Detector{}))
+		case "gcp":
+			options = append(options, resource.WithDetectors(gcp.NewDetector()))
+		default:
+			return nil, fmt.Errorf("unsupported resource detector %q", name)
+		}
+	}
+
+	return options, nil
+}
+
+const (
+	ec2MetadataURL     = "http://169.254.169.254/latest"
+	ec2MetadataTimeout = time.Second
+)
+
+var _ resource.Detector = ec2Detector{}
+
+// ec2Detector reads the instance identity document from the EC2 instance
+// metadata service using IMDSv2. Off EC2 the request times out quickly and
+// the detector adds nothing.
+type ec2Detector struct{}
+
+func (ec2Detector) Detect(ctx context.Context) (*resource.Resource, error) {
+	ctx, cancel := context.WithTimeout(ctx, ec2MetadataTimeout)
+	defer cancel()
+
+	token, err := ec2MetadataRequest(ctx, http.MethodPut, "/api/token", "X-aws-ec2-metadata-token-ttl-seconds", "60")
+	if err != nil {
+		return resource.Empty(), nil
+	}
+
+	document, err := ec2MetadataRequest(ctx, http.MethodGet, "/dynamic/instance-identity/document", "X-aws-ec2-metadata-token", string(token))
+	if err != nil {
+		return resource.Empty(), nil
+	}
+
+	var identity struct {
+		AccountID        string `json:"accountId"`
+		AvailabilityZone string `json:"availabilityZone"`
+		Region           string `json:"region"`
+		InstanceID       string `json:"instanceId"`
+		InstanceType     string `json:"instanceType"`
+		ImageID          string `json:"imageId"`
+	}
+	if err := json.Unmarshal(document, &identity); err != nil {
+		return nil, fmt.Errorf("%w: failed to decode ec2 instance identity: %v", resource.ErrPartialResource, err)
+	}
+
+	attrs := []attribute.KeyValue{
+		semconv.CloudProviderAWS,
+		semconv.CloudPlatformAWSEC2,
+	}
+	for key, value := range map[attribute.Key]string{
+		semconv.CloudAccountIDKey:        identity.AccountID,
+		semconv.CloudAvailabilityZoneKey: identity.AvailabilityZone,
+		semconv.CloudRegionKey:           identity.Region,
+		semconv.HostIDKey:                identity.InstanceID,
+		semconv.HostTypeKey:              identity.InstanceType,
+		semconv.HostImageIDKey:           identity.ImageID,
+	} {
+		if value != "" {
+			attrs = append(attrs, key.String(value))
+		}
+	}
+
+	return resource.NewSchemaless(attrs...), nil
+}
+
+func ec2MetadataRequest(ctx context.Context, method, path, header, value string) ([]byte, error) {
+	req, err := http.NewRequestWithContext(ctx, method, ec2MetadataURL+path, nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set(header, value)
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("unexpected status %s", resp.Status)
+	}
+
+	return io.ReadAll(resp.Body)
+}
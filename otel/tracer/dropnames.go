@@ -0,0 +1,86 @@
+package tracer
+
+import (
+	"fmt"
+	"path"
+	"strings"
+
+	"go.opentelemetry.io/otel/attribute"
+	sdkTrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// spanNamePatterns matches span names against path.Match patterns, so *
+// does not cross a /: "GET /healthz" and "GET /metrics/*" are typical.
+type spanNamePatterns []string
+
+func newSpanNamePatterns(patterns []string) (spanNamePatterns, error) {
+	for _, p := range patterns {
+		if _, err := path.Match(p, ""); err != nil {
+			return nil, fmt.Errorf("invalid span name pattern %q: %w", p, err)
+		}
+	}
+
+	return patterns, nil
+}
+
+func (p spanNamePatterns) match(name string) bool {
+	if name == "" {
+		return false
+	}
+
+	for _, pattern := range p {
+		if ok, _ := path.Match(pattern, name); ok {
+			return true
+		}
+	}
+
+	return false
+}
+
+// requestName returns "METHOD /path" from the HTTP attributes of a span
+// that is about to start. Server spans are often renamed to their route
+// only when they end, so this is what drop rules see at sampling time.
+func requestName(attrs []attribute.KeyValue) string {
+	var method, target string
+	for _, kv := range attrs {
+		switch kv.Key {
+		case "http.method", "http.request.method":
+			method = kv.Value.AsString()
+		case "http.target", "url.path":
+			target = kv.Value.AsString()
+		}
+	}
+	if method == "" || target == "" {
+		return ""
+	}
+	if i := strings.IndexByte(target, '?'); i >= 0 {
+		target = target[:i]
+	}
+
+	return method + " " + target
+}
+
+var _ sdkTrace.Sampler = (*nameDropSampler)(nil)
+
+// nameDropSampler never samples spans whose name or HTTP request matches
+// one of its patterns and defers to its sampler for the others.
+type nameDropSampler struct {
+	sdkTrace.Sampler
+	patterns spanNamePatterns
+}
+
+func (s nameDropSampler) ShouldSample(p sdkTrace.SamplingParameters) sdkTrace.SamplingResult {
+	if s.patterns.match(p.Name) || s.patterns.match(requestName(p.Attributes)) {
+		return sdkTrace.SamplingResult{
+			Decision:   sdkTrace.Drop,
+			Tracestate: trace.SpanContextFromContext(p.ParentContext).TraceState(),
+		}
+	}
+
+	return s.Sampler.ShouldSample(p)
+}
+
+func (s nameDropSampler) Description() string {
+	return fmt.Sprintf("DropSpanNames{%s}+%s", strings.Join(s.patterns, ","), s.Sampler.Description())
+}
@@ -0,0 +1,85 @@
+package tracer
+
+import (
+	"context"
+	"fmt"
+
+	"go.opentelemetry.io/otel/attribute"
+	sdkTrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/trace"
+)
+
+const correlationIDKey = attribute.Key("correlation.id")
+
+var _ sdkTrace.SpanProcessor = (*correlationIDProcessor)(nil)
+
+// correlationIDProcessor copies a correlation ID stored in the parent
+// context onto every span as it starts.
+type correlationIDProcessor struct {
+	contextKey any
+}
+
+func (p *correlationIDProcessor) OnStart(parent context.Context, s sdkTrace.ReadWriteSpan) {
+	switch id := parent.Value(p.contextKey).(type) {
+	case nil:
+	case string:
+		if id != "" {
+			s.SetAttributes(correlationIDKey.String(id))
+		}
+	case fmt.Stringer:
+		s.SetAttributes(correlationIDKey.String(id.String()))
+	}
+}
+
+func (p *correlationIDProcessor) OnEnd(s sdkTrace.ReadOnlySpan) {}
+
+func (p *correlationIDProcessor) Shutdown(ctx context.Context) error {
+	return nil
+}
+
+func (p *correlationIDProcessor) ForceFlush(ctx context.Context) error {
+	return nil
+}
+
+type requestAttributesKey struct{}
+
+// AddRequestAttribute returns a copy of ctx carrying kv, which is set on the
+// span in ctx and on every span started later from the returned context or
+// its descendants.
+func AddRequestAttribute(ctx context.Context, kv attribute.KeyValue) context.Context {
+	trace.SpanFromContext(ctx).SetAttributes(kv)
+
+	attrs := requestAttributes(ctx)
+	next := make([]attribute.KeyValue, len(attrs), len(attrs)+1)
+	copy(next, attrs)
+	next = append(next, kv)
+
+	return context.WithValue(ctx, requestAttributesKey{}, next)
+}
+
+func requestAttributes(ctx context.Context) []attribute.KeyValue {
+	attrs, _ := ctx.Value(requestAttributesKey{}).([]attribute.KeyValue)
+	return attrs
+}
+
+var _ sdkTrace.SpanProcessor = (*requestAttributesProcessor)(nil)
+
+// requestAttributesProcessor sets the attributes added with
+// AddRequestAttribute on spans as they start.
+type requestAttributesProcessor struct{}
+
+func (p *requestAttributesProcessor) OnStart(parent context.Context, s sdkTrace.ReadWriteSpan) {
+	if attrs := requestAttributes(parent); len(attrs) > 0 {
+		s.SetAttributes(attrs...)
+	}
+}
+
+func (p *requestAttributesProcessor) OnEnd(s sdkTrace.ReadOnlySpan) {}
+
+func (p *requestAttributesProcessor) Shutdown(ctx context.Context) error {
+	return nil
+}
+
+func (p *requestAttributesProcessor) ForceFlush(ctx context.Context) error {
+	return nil
+}
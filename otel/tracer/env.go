@@ -0,0 +1,176 @@
+package tracer
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+
+	"go.opentelemetry.io/otel/attribute"
+	sdkTrace "go.opentelemetry.io/otel/sdk/trace"
+
+	"github.com/0x5w4/go-otel/otel/internal/otlpconfig"
+)
+
+// ConfigFromEnv returns a Config populated from the standard OTEL_*
+// environment variables. See MergeEnv for the variables read.
+func ConfigFromEnv() (*Config, error) {
+	cfg := new(Config)
+	if err := cfg.MergeEnv(); err != nil {
+		return nil, err
+	}
+
+	return cfg, nil
+}
+
+// MergeEnv fills the fields of c that are still unset from the standard
+// OTEL_* environment variables, so explicit configuration takes precedence
+// over the environment as the specification requires. It reads:
+//
+//   - OTEL_EXPORTER_OTLP_TRACES_ENDPOINT, OTEL_EXPORTER_OTLP_ENDPOINT
+//   - OTEL_EXPORTER_OTLP_TRACES_PROTOCOL, OTEL_EXPORTER_OTLP_PROTOCOL
+//   - OTEL_EXPORTER_OTLP_TRACES_HEADERS, OTEL_EXPORTER_OTLP_HEADERS
+//   - OTEL_EXPORTER_OTLP_TRACES_CERTIFICATE, OTEL_EXPORTER_OTLP_CERTIFICATE
+//   - OTEL_EXPORTER_OTLP_TRACES_CLIENT_CERTIFICATE, OTEL_EXPORTER_OTLP_CLIENT_CERTIFICATE
+//   - OTEL_EXPORTER_OTLP_TRACES_CLIENT_KEY, OTEL_EXPORTER_OTLP_CLIENT_KEY
+//   - OTEL_EXPORTER_OTLP_TRACES_INSECURE, OTEL_EXPORTER_OTLP_INSECURE
+//   - OTEL_EXPORTER_OTLP_TRACES_COMPRESSION, OTEL_EXPORTER_OTLP_COMPRESSION
+//   - OTEL_SERVICE_NAME
+//   - OTEL_RESOURCE_ATTRIBUTES
+//   - OTEL_TRACES_SAMPLER, OTEL_TRACES_SAMPLER_ARG
+//   - OTEL_PROPAGATORS
+//
+// Headers and resource attributes are merged key by key. The sampler
+// variables set SamplerSpec, and only when none of Sampler, SamplerSpec and
+// TargetSpansPerSecond is set.
+func (c *Config) MergeEnv() error {
+	if c.Protocol == "" {
+		c.Protocol = otlpconfig.FirstEnv("OTEL_EXPORTER_OTLP_TRACES_PROTOCOL", "OTEL_EXPORTER_OTLP_PROTOCOL")
+	}
+
+	if c.ExporterURL == "" {
+		if v := os.Getenv("OTEL_EXPORTER_OTLP_TRACES_ENDPOINT"); v != "" {
+			c.ExporterURL = v
+		} else if v := os.Getenv("OTEL_EXPORTER_OTLP_ENDPOINT"); v != "" {
+			c.ExporterURL = signalURL(v, c.Protocol)
+		}
+	}
+
+	if c.ServiceName == "" {
+		c.ServiceName = os.Getenv("OTEL_SERVICE_NAME")
+	}
+
+	if c.TLSCAFile == "" {
+		c.TLSCAFile = otlpconfig.FirstEnv("OTEL_EXPORTER_OTLP_TRACES_CERTIFICATE", "OTEL_EXPORTER_OTLP_CERTIFICATE")
+	}
+	if c.TLSCertFile == "" {
+		c.TLSCertFile = otlpconfig.FirstEnv("OTEL_EXPORTER_OTLP_TRACES_CLIENT_CERTIFICATE", "OTEL_EXPORTER_OTLP_CLIENT_CERTIFICATE")
+	}
+	if c.TLSKeyFile == "" {
+		c.TLSKeyFile = otlpconfig.FirstEnv("OTEL_EXPORTER_OTLP_TRACES_CLIENT_KEY", "OTEL_EXPORTER_OTLP_CLIENT_KEY")
+	}
+	if !c.Insecure {
+		if v := otlpconfig.FirstEnv("OTEL_EXPORTER_OTLP_TRACES_INSECURE", "OTEL_EXPORTER_OTLP_INSECURE"); v != "" {
+			insecure, err := strconv.ParseBool(v)
+			if err != nil {
+				return fmt.Errorf("invalid OTEL_EXPORTER_OTLP_INSECURE %q", v)
+			}
+			c.Insecure = insecure
+		}
+	}
+
+	if c.Compression == "" {
+		c.Compression = otlpconfig.FirstEnv("OTEL_EXPORTER_OTLP_TRACES_COMPRESSION", "OTEL_EXPORTER_OTLP_COMPRESSION")
+	}
+
+	headers, err := otlpconfig.MergeEnvHeaders(c.Headers, "TRACES")
+	if err != nil {
+		return err
+	}
+	c.Headers = headers
+
+	resourceAttrs, err := otlpconfig.ParseKeyValues(os.Getenv("OTEL_RESOURCE_ATTRIBUTES"))
+	if err != nil {
+		return fmt.Errorf("invalid OTEL_RESOURCE_ATTRIBUTES: %w", err)
+	}
+	// Config.Attributes take precedence, so environment attributes go first.
+	var envAttrs []attribute.KeyValue
+	for k, v := range resourceAttrs {
+		envAttrs = append(envAttrs, attribute.String(k, v))
+	}
+	c.Attributes = append(envAttrs, c.Attributes...)
+
+	if c.Propagators == nil {
+		for _, name := range strings.Split(os.Getenv("OTEL_PROPAGATORS"), ",") {
+			if name = strings.TrimSpace(name); name != "" {
+				c.Propagators = append(c.Propagators, name)
+			}
+		}
+	}
+
+	// Any of the sampler fields counts as explicit sampling configuration.
+	if c.Sampler == nil && c.SamplerSpec == "" && c.TargetSpansPerSecond == 0 {
+		if name := os.Getenv("OTEL_TRACES_SAMPLER"); name != "" {
+			arg := os.Getenv("OTEL_TRACES_SAMPLER_ARG")
+			if _, err := samplerFromEnv(name, arg); err != nil {
+				return fmt.Errorf("invalid OTEL_TRACES_SAMPLER: %w", err)
+			}
+			c.SamplerSpec = name
+			if arg != "" {
+				c.SamplerSpec += ":" + arg
+			}
+		}
+	}
+
+	return nil
+}
+
+// signalURL derives the traces URL from OTEL_EXPORTER_OTLP_ENDPOINT. For
+// OTLP/HTTP the specification appends the signal path to the base URL.
+func signalURL(base, protocol string) string {
+	if protocol != ProtocolHTTPProtobuf {
+		return base
+	}
+
+	return strings.TrimSuffix(base, "/") + "/v1/traces"
+}
+
+// samplerFromEnv builds the sampler named by OTEL_TRACES_SAMPLER with the
+// ratio argument of OTEL_TRACES_SAMPLER_ARG.
+func samplerFromEnv(name, arg string) (sdkTrace.Sampler, error) {
+	ratio := func() (float64, error) {
+		if arg == "" {
+			return 1, nil
+		}
+		r, err := strconv.ParseFloat(arg, 64)
+		if err != nil || r < 0 || r > 1 {
+			return 0, fmt.Errorf("invalid sampler ratio %q", arg)
+		}
+		return r, nil
+	}
+
+	switch name {
+	case "always_on":
+		return sdkTrace.AlwaysSample(), nil
+	case "always_off":
+		return sdkTrace.NeverSample(), nil
+	case "traceidratio":
+		r, err := ratio()
+		if err != nil {
+			return nil, err
+		}
+		return sdkTrace.TraceIDRatioBased(r), nil
+	case "parentbased_always_on":
+		return sdkTrace.ParentBased(sdkTrace.AlwaysSample()), nil
+	case "parentbased_always_off":
+		return sdkTrace.ParentBased(sdkTrace.NeverSample()), nil
+	case "parentbased_traceidratio":
+		r, err := ratio()
+		if err != nil {
+			return nil, err
+		}
+		return sdkTrace.ParentBased(sdkTrace.TraceIDRatioBased(r)), nil
+	default:
+		return nil, fmt.Errorf("unsupported sampler %q", name)
+	}
+}
@@ -0,0 +1,57 @@
+package tracer
+
+import (
+	"context"
+
+	"go.opentelemetry.io/otel/log"
+	"go.opentelemetry.io/otel/log/global"
+	sdkTrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/trace"
+)
+
+var _ sdkTrace.SpanProcessor = (*eventLogProcessor)(nil)
+
+// eventLogProcessor emits one log record per span event when a span ends.
+// Records are emitted with the span context so log backends can correlate
+// them by trace and span ID.
+type eventLogProcessor struct {
+	logger log.Logger
+}
+
+func newEventLogProcessor() *eventLogProcessor {
+	return &eventLogProcessor{
+		logger: global.Logger(instrumentationName),
+	}
+}
+
+func (p *eventLogProcessor) OnStart(parent context.Context, s sdkTrace.ReadWriteSpan) {}
+
+func (p *eventLogProcessor) OnEnd(s sdkTrace.ReadOnlySpan) {
+	events := s.Events()
+	if len(events) == 0 {
+		return
+	}
+
+	ctx := trace.ContextWithSpanContext(context.Background(), s.SpanContext())
+	for _, event := range events {
+		var record log.Record
+		record.SetTimestamp(event.Time)
+		record.SetEventName(event.Name)
+		record.SetBody(log.StringValue(event.Name))
+		record.SetSeverity(log.SeverityInfo)
+		record.AddAttributes(log.String("span.name", s.Name()))
+		for _, kv := range event.Attributes {
+			record.AddAttributes(log.KeyValueFromAttribute(kv))
+		}
+
+		p.logger.Emit(ctx, record)
+	}
+}
+
+func (p *eventLogProcessor) Shutdown(ctx context.Context) error {
+	return nil
+}
+
+func (p *eventLogProcessor) ForceFlush(ctx context.Context) error {
+	return nil
+}
@@ -0,0 +1,91 @@
+package tracer
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	sdkTrace "go.opentelemetry.io/otel/sdk/trace"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// ErrExportUnauthorized is wrapped into export errors caused by the
+// collector rejecting the credentials, which usually means an expired or
+// rotated token rather than a transient failure.
+var ErrExportUnauthorized = errors.New("otlp export unauthorized")
+
+var _ sdkTrace.SpanExporter = (*hookExporter)(nil)
+
+// hookExporter wraps a SpanExporter, remembers the outcome of the most
+// recent ExportSpans call and reports it to onExport.
+type hookExporter struct {
+	sdkTrace.SpanExporter
+	onExport func(spanCount int, err error)
+
+	mu   sync.RWMutex
+	last ExportStatus
+}
+
+// ExportStatus describes the most recent export of a tracer.
+type ExportStatus struct {
+	// Time is when the export finished, zero if nothing has been exported.
+	Time      time.Time
+	SpanCount int
+	Err       error
+}
+
+func (e *hookExporter) ExportSpans(ctx context.Context, spans []sdkTrace.ReadOnlySpan) error {
+	err := classifyExportError(e.SpanExporter.ExportSpans(ctx, spans))
+
+	e.mu.Lock()
+	e.last = ExportStatus{Time: time.Now(), SpanCount: len(spans), Err: err}
+	e.mu.Unlock()
+
+	if e.onExport != nil {
+		e.onExport(len(spans), err)
+	}
+
+	return err
+}
+
+func (e *hookExporter) lastStatus() ExportStatus {
+	e.mu.RLock()
+	defer e.mu.RUnlock()
+
+	return e.last
+}
+
+func classifyExportError(err error) error {
+	if err == nil {
+		return nil
+	}
+
+	switch status.Code(err) {
+	case codes.Unauthenticated, codes.PermissionDenied:
+		return fmt.Errorf("%w: %w", ErrExportUnauthorized, err)
+	}
+	if httpStatusUnauthorized(err) {
+		return fmt.Errorf("%w: %w", ErrExportUnauthorized, err)
+	}
+
+	return err
+}
+
+// httpStatusUnauthorized reports whether err is the otlptracehttp error for
+// a 401 or 403 response. The exporter does not expose the status code, only
+// the text "failed to send to <url>: <status> (body: ...)".
+func httpStatusUnauthorized(err error) bool {
+	msg := err.Error()
+	i := strings.Index(msg, "failed to send to ")
+	if i < 0 {
+		return false
+	}
+	// The body is left out so that its contents cannot match.
+	msg, _, _ = strings.Cut(msg[i:], " (body: ")
+
+	return strings.Contains(msg, ": 401 ") || strings.Contains(msg, ": 403 ")
+}
@@ -0,0 +1,98 @@
+package tracer
+
+import (
+	"fmt"
+	"path"
+	"strings"
+
+	sdkTrace "go.opentelemetry.io/otel/sdk/trace"
+)
+
+// ExportFilter keeps spans from being exported by their instrumentation
+// scope and span name. A span is exported when its scope and name match
+// an allow pattern, or the allow list is empty, and match no deny pattern.
+type ExportFilter struct {
+	// AllowScopes and DenyScopes are path.Match patterns of
+	// instrumentation scope names, in which a trailing * also crosses /:
+	// "github.com/redis/go-redis/*" covers every scope of the module.
+	AllowScopes []string
+	DenyScopes  []string
+
+	// AllowSpanNames and DenySpanNames are path.Match patterns of span
+	// names, e.g. "redis.*".
+	AllowSpanNames []string
+	DenySpanNames  []string
+}
+
+func (f ExportFilter) enabled() bool {
+	return len(f.AllowScopes) > 0 || len(f.DenyScopes) > 0 || len(f.AllowSpanNames) > 0 || len(f.DenySpanNames) > 0
+}
+
+// exportFilter is an ExportFilter with its patterns checked.
+type exportFilter struct {
+	allowScopes scopePatterns
+	denyScopes  scopePatterns
+	allowNames  spanNamePatterns
+	denyNames   spanNamePatterns
+}
+
+func newExportFilter(f ExportFilter) (*exportFilter, error) {
+	filter := new(exportFilter)
+
+	var err error
+	if filter.allowScopes, err = newScopePatterns(f.AllowScopes); err != nil {
+		return nil, err
+	}
+	if filter.denyScopes, err = newScopePatterns(f.DenyScopes); err != nil {
+		return nil, err
+	}
+	if filter.allowNames, err = newSpanNamePatterns(f.AllowSpanNames); err != nil {
+		return nil, err
+	}
+	if filter.denyNames, err = newSpanNamePatterns(f.DenySpanNames); err != nil {
+		return nil, err
+	}
+
+	return filter, nil
+}
+
+// drop reports whether s must not be exported.
+func (f *exportFilter) drop(s sdkTrace.ReadOnlySpan) bool {
+	scope, name := s.InstrumentationScope().Name, s.Name()
+
+	if len(f.allowScopes) > 0 && !f.allowScopes.match(scope) {
+		return true
+	}
+	if len(f.allowNames) > 0 && !f.allowNames.match(name) {
+		return true
+	}
+
+	return f.denyScopes.match(scope) || f.denyNames.match(name)
+}
+
+// scopePatterns matches instrumentation scope names against path.Match
+// patterns, with a trailing * matching any suffix.
+type scopePatterns []string
+
+func newScopePatterns(patterns []string) (scopePatterns, error) {
+	for _, p := range patterns {
+		if _, err := path.Match(p, ""); err != nil {
+			return nil, fmt.Errorf("invalid scope pattern %q: %w", p, err)
+		}
+	}
+
+	return patterns, nil
+}
+
+func (p scopePatterns) match(scope string) bool {
+	for _, pattern := range p {
+		if prefix, ok := strings.CutSuffix(pattern, "*"); ok && strings.HasPrefix(scope, prefix) {
+			return true
+		}
+		if ok, _ := path.Match(pattern, scope); ok {
+			return true
+		}
+	}
+
+	return false
+}
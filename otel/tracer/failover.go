@@ -0,0 +1,111 @@
+package tracer
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"sync"
+	"time"
+
+	sdkTrace "go.opentelemetry.io/otel/sdk/trace"
+)
+
+const defaultFailoverThreshold = 3
+
+// newExporter builds the OTLP exporter of cfg, wrapped in a failoverExporter
+// when a FailoverURL is configured.
+func newExporter(ctx context.Context, cfg *Config) (sdkTrace.SpanExporter, error) {
+	primary, err := newOTLPExporter(ctx, cfg)
+	if err != nil {
+		return nil, err
+	}
+	if cfg.FailoverURL == "" {
+		return primary, nil
+	}
+
+	failoverCfg := *cfg
+	failoverCfg.ExporterURL = cfg.FailoverURL
+	secondary, err := newOTLPExporter(ctx, &failoverCfg)
+	if err != nil {
+		_ = primary.Shutdown(ctx)
+		return nil, err
+	}
+
+	threshold := cfg.FailoverThreshold
+	if threshold <= 0 {
+		threshold = defaultFailoverThreshold
+	}
+	logger := cfg.Logger
+	if logger == nil {
+		logger = slog.Default()
+	}
+
+	return &failoverExporter{
+		exporters: [2]sdkTrace.SpanExporter{primary, secondary},
+		threshold: threshold,
+		retry:     cfg.FailoverRetryPrimary,
+		logger:    logger,
+	}, nil
+}
+
+var _ sdkTrace.SpanExporter = (*failoverExporter)(nil)
+
+// failoverExporter exports to a primary exporter and switches to a
+// secondary one after threshold consecutive failed exports, retrying the
+// failed batch there. With a retry interval it tries the primary again
+// once that long has passed since the switch.
+type failoverExporter struct {
+	exporters [2]sdkTrace.SpanExporter
+	threshold int
+	retry     time.Duration
+	logger    *slog.Logger
+
+	mu       sync.Mutex
+	active   int
+	failures int
+	switched time.Time
+}
+
+func (e *failoverExporter) ExportSpans(ctx context.Context, spans []sdkTrace.ReadOnlySpan) error {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	if e.active == 1 && e.retry > 0 && time.Since(e.switched) >= e.retry {
+		if err := e.exporters[0].ExportSpans(ctx, spans); err == nil {
+			e.activate(0)
+			return nil
+		}
+		e.switched = time.Now()
+	}
+
+	err := e.exporters[e.active].ExportSpans(ctx, spans)
+	if err == nil {
+		e.failures = 0
+		return nil
+	}
+
+	e.failures++
+	if e.active == 1 || e.failures < e.threshold {
+		return err
+	}
+
+	e.activate(1)
+	return e.exporters[1].ExportSpans(ctx, spans)
+}
+
+// activate switches to the exporter at index i.
+func (e *failoverExporter) activate(i int) {
+	e.active = i
+	e.failures = 0
+	e.switched = time.Now()
+
+	if i == 1 {
+		e.logger.Warn("otlp exports failing, switched to the failover endpoint")
+	} else {
+		e.logger.Info("otlp exports switched back to the primary endpoint")
+	}
+}
+
+func (e *failoverExporter) Shutdown(ctx context.Context) error {
+	return errors.Join(e.exporters[0].Shutdown(ctx), e.exporters[1].Shutdown(ctx))
+}
@@ -0,0 +1,198 @@
+package tracer
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"sync"
+
+	sdkTrace "go.opentelemetry.io/otel/sdk/trace"
+)
+
+// FileConfig configures the file exporter.
+type FileConfig struct {
+	// Path is the file spans are appended to.
+	Path string
+	// MaxSizeBytes rotates the file before a write would grow it beyond
+	// this size. Zero disables rotation.
+	MaxSizeBytes int64
+	// MaxFiles is the number of rotated files kept next to Path as
+	// Path.1 (the newest) to Path.N. With zero the file is truncated on
+	// rotation.
+	MaxFiles int
+}
+
+var _ sdkTrace.SpanExporter = (*fileExporter)(nil)
+
+// fileExporter appends every batch of spans to a file as one line of
+// OTLP/JSON, the format read by the OpenTelemetry Collector's otlpjsonfile
+// receiver, so log forwarders can ship the spans later.
+type fileExporter struct {
+	cfg FileConfig
+
+	mu     sync.Mutex
+	file   *os.File
+	size   int64
+	closed bool
+}
+
+// NewFileExporter returns an exporter writing spans to the file of cfg as
+// JSON lines, for InitFileTracer or Config.AdditionalExporters.
+func NewFileExporter(cfg FileConfig) (sdkTrace.SpanExporter, error) {
+	if cfg.Path == "" {
+		return nil, errors.New("path is missing in the file exporter configuration")
+	}
+
+	e := &fileExporter{cfg: cfg}
+	if err := e.open(); err != nil {
+		return nil, err
+	}
+
+	return e, nil
+}
+
+// InitFileTracer is InitTracer for air-gapped environments: spans are
+// written to a rotating file as OTLP/JSON lines instead of being sent to a
+// collector, so no exporter settings are required.
+func InitFileTracer(ctx context.Context, file FileConfig, opts ...Option) (Tracer, error) {
+	cfg := new(Config)
+	for _, opt := range opts {
+		opt.apply(cfg)
+	}
+
+	serviceName := resolveServiceName(cfg)
+	if serviceName == "" {
+		return nil, fmt.Errorf("service name is missing in the file tracer configuration")
+	}
+
+	exporter, err := NewFileExporter(file)
+	if err != nil {
+		return nil, err
+	}
+
+	t, err := newTracer(ctx, cfg, serviceName, exporter)
+	if err != nil {
+		return nil, err
+	}
+
+	return t, nil
+}
+
+func (e *fileExporter) open() error {
+	f, err := os.OpenFile(e.cfg.Path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0o644)
+	if err != nil {
+		return fmt.Errorf("failed to open span file: %w", err)
+	}
+
+	info, err := f.Stat()
+	if err != nil {
+		f.Close()
+		return fmt.Errorf("failed to open span file: %w", err)
+	}
+
+	e.file = f
+	e.size = info.Size()
+
+	return nil
+}
+
+func (e *fileExporter) ExportSpans(ctx context.Context, spans []sdkTrace.ReadOnlySpan) error {
+	if len(spans) == 0 {
+		return nil
+	}
+
+	line, err := json.Marshal(otlpJSONTraces(spans))
+	if err != nil {
+		return fmt.Errorf("failed to encode spans: %w", err)
+	}
+	line = append(line, '\n')
+
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	if e.closed {
+		return nil
+	}
+	// A failed rotation or reopen leaves no file open; try again rather
+	// than losing every later batch.
+	if e.file == nil {
+		if err := e.open(); err != nil {
+			return err
+		}
+	}
+
+	var rotateErr error
+	if e.cfg.MaxSizeBytes > 0 && e.size > 0 && e.size+int64(len(line)) > e.cfg.MaxSizeBytes {
+		// The batch still goes to the reopened file when rotating fails.
+		if rotateErr = e.rotate(); e.file == nil {
+			return rotateErr
+		}
+	}
+
+	n, err := e.file.Write(line)
+	e.size += int64(n)
+	if err != nil {
+		return errors.Join(rotateErr, fmt.Errorf("failed to write spans: %w", err))
+	}
+
+	return rotateErr
+}
+
+// rotate shifts Path.i to Path.i+1, dropping the oldest file, moves Path to
+// Path.1 and reopens Path. When a step fails, Path is reopened as it is, so
+// spans keep being written to it.
+func (e *fileExporter) rotate() error {
+	if err := e.file.Close(); err != nil {
+		return fmt.Errorf("failed to close span file: %w", err)
+	}
+	e.file = nil
+
+	if err := e.shift(); err != nil {
+		return errors.Join(err, e.open())
+	}
+
+	return e.open()
+}
+
+func (e *fileExporter) shift() error {
+	path := e.cfg.Path
+	if e.cfg.MaxFiles <= 0 {
+		if err := os.Remove(path); err != nil && !errors.Is(err, fs.ErrNotExist) {
+			return fmt.Errorf("failed to rotate span file: %w", err)
+		}
+		return nil
+	}
+
+	for i := e.cfg.MaxFiles - 1; i >= 1; i-- {
+		err := os.Rename(fmt.Sprintf("%s.%d", path, i), fmt.Sprintf("%s.%d", path, i+1))
+		if err != nil && !errors.Is(err, fs.ErrNotExist) {
+			return fmt.Errorf("failed to rotate span file: %w", err)
+		}
+	}
+	if err := os.Rename(path, path+".1"); err != nil {
+		return fmt.Errorf("failed to rotate span file: %w", err)
+	}
+
+	return nil
+}
+
+func (e *fileExporter) Shutdown(ctx context.Context) error {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	e.closed = true
+	if e.file == nil {
+		return nil
+	}
+
+	err := e.file.Close()
+	e.file = nil
+	if err != nil {
+		return fmt.Errorf("failed to close span file: %w", err)
+	}
+
+	return nil
+}
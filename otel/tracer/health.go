@@ -0,0 +1,70 @@
+package tracer
+
+import (
+	"context"
+	"fmt"
+	"net"
+)
+
+// HealthCheck verifies that the collector is reachable by opening and
+// closing a connection to the exporter endpoint within ctx, then
+// returns the error of the most recent export, if any, so readiness probes
+// also catch rejected credentials. Tracers that do not export over OTLP
+// always report healthy.
+func (t *otelTracer) HealthCheck(ctx context.Context) error {
+	collector := t.collector.Load()
+	if collector == nil || (collector.host == "" && collector.scheme != schemeUnix) {
+		return nil
+	}
+
+	var d net.Dialer
+	conn, err := d.DialContext(ctx, collector.dialNetwork(), collector.dialAddress())
+	if err != nil {
+		return fmt.Errorf("otlp collector unreachable: %w", err)
+	}
+	conn.Close()
+
+	if err := t.LastExportError(); err != nil {
+		return fmt.Errorf("last otlp export failed: %w", err)
+	}
+
+	return nil
+}
+
+// LastExportStatus describes the most recent export. It is the zero value
+// until the first export and for tracers that do not export over OTLP.
+func (t *otelTracer) LastExportStatus() ExportStatus {
+	if t.exporter == nil {
+		return ExportStatus{}
+	}
+
+	return t.exporter.lastStatus()
+}
+
+func (e endpointURL) dialNetwork() string {
+	if e.scheme == schemeUnix {
+		return "unix"
+	}
+
+	return "tcp"
+}
+
+// dialAddress returns the host:port to connect to, adding the default port
+// of the scheme to hosts without one, or the path of a unix socket.
+func (e endpointURL) dialAddress() string {
+	if e.scheme == schemeUnix {
+		return e.path
+	}
+	if _, _, err := net.SplitHostPort(e.host); err == nil {
+		return e.host
+	}
+
+	switch e.scheme {
+	case "https":
+		return net.JoinHostPort(e.host, "443")
+	case "http":
+		return net.JoinHostPort(e.host, "80")
+	default:
+		return net.JoinHostPort(e.host, "4317")
+	}
+}
@@ -0,0 +1,56 @@
+package tracer
+
+import (
+	"context"
+
+	"go.opentelemetry.io/otel/trace"
+)
+
+// SpanContextFields describes the span context in ctx for structured logs.
+// Without a valid span context the IDs are empty strings and sampled is
+// false.
+func SpanContextFields(ctx context.Context) map[string]any {
+	sc := trace.SpanContextFromContext(ctx)
+
+	fields := map[string]any{
+		"trace_id":    "",
+		"span_id":     "",
+		"trace_flags": sc.TraceFlags().String(),
+		"sampled":     sc.IsSampled(),
+	}
+	if sc.HasTraceID() {
+		fields["trace_id"] = sc.TraceID().String()
+	}
+	if sc.HasSpanID() {
+		fields["span_id"] = sc.SpanID().String()
+	}
+
+	return fields
+}
+
+// TraceIDFromContext returns the hex trace ID of the span in ctx, or an empty
+// string without a valid span context.
+func TraceIDFromContext(ctx context.Context) string {
+	sc := trace.SpanContextFromContext(ctx)
+	if !sc.HasTraceID() {
+		return ""
+	}
+
+	return sc.TraceID().String()
+}
+
+// SpanIDFromContext returns the hex span ID of the span in ctx, or an empty
+// string without a valid span context.
+func SpanIDFromContext(ctx context.Context) string {
+	sc := trace.SpanContextFromContext(ctx)
+	if !sc.HasSpanID() {
+		return ""
+	}
+
+	return sc.SpanID().String()
+}
+
+// IsSampled reports whether the span in ctx is sampled.
+func IsSampled(ctx context.Context) bool {
+	return trace.SpanContextFromContext(ctx).IsSampled()
+}
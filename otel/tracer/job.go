@@ -0,0 +1,96 @@
+package tracer
+
+import (
+	"context"
+	"time"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/metric"
+	"go.opentelemetry.io/otel/trace"
+)
+
+const (
+	jobNameKey   = attribute.Key("job.name")
+	jobResultKey = attribute.Key("job.result")
+)
+
+type jobConfig struct {
+	meterProvider metric.MeterProvider
+	attrs         []attribute.KeyValue
+}
+
+type JobOption func(c *jobConfig)
+
+// WithJobMetrics records the duration of every run in the job.run.duration
+// histogram of mp, with the job.name and job.result ("success" or
+// "failure") attributes. A nil mp uses the global MeterProvider.
+func WithJobMetrics(mp metric.MeterProvider) JobOption {
+	return func(c *jobConfig) {
+		if mp == nil {
+			mp = otel.GetMeterProvider()
+		}
+		c.meterProvider = mp
+	}
+}
+
+// WithJobAttributes sets attrs on the span of every run.
+func WithJobAttributes(attrs ...attribute.KeyValue) JobOption {
+	return func(c *jobConfig) {
+		c.attrs = append(c.attrs, attrs...)
+	}
+}
+
+// InstrumentJob wraps fn, a cron or background job, so that every run is
+// traced as a new root span named name, with errors and panics recorded on
+// it. The span of the calling context, if any, is linked rather than used
+// as parent, so each run is a trace of its own.
+func InstrumentJob(name string, fn func(ctx context.Context) error, opts ...JobOption) func(ctx context.Context) error {
+	var cfg jobConfig
+	for _, opt := range opts {
+		opt(&cfg)
+	}
+
+	var duration metric.Float64Histogram
+	if cfg.meterProvider != nil {
+		var err error
+		duration, err = cfg.meterProvider.Meter(instrumentationName).Float64Histogram("job.run.duration",
+			metric.WithDescription("Duration of background job runs."),
+			metric.WithUnit("s"),
+		)
+		if err != nil {
+			otel.Handle(err)
+		}
+	}
+
+	attrs := append([]attribute.KeyValue{jobNameKey.String(name)}, cfg.attrs...)
+
+	return func(ctx context.Context) error {
+		startOpts := []trace.SpanStartOption{trace.WithNewRoot(), trace.WithAttributes(attrs...)}
+		if sc := trace.SpanContextFromContext(ctx); sc.IsValid() {
+			startOpts = append(startOpts, trace.WithLinks(LinkFromSpanContext(sc)))
+		}
+
+		ctx, span := StartSpan(ctx, name, startOpts...)
+		defer span.End()
+		defer RecoverAndRecord(ctx)
+
+		start := time.Now()
+		err := fn(ctx)
+		if err != nil {
+			span.RecordError(err)
+			span.SetStatus(codes.Error, err.Error())
+		}
+
+		if duration != nil {
+			result := "success"
+			if err != nil {
+				result = "failure"
+			}
+			duration.Record(ctx, time.Since(start).Seconds(), metric.WithAttributes(jobNameKey.String(name), jobResultKey.String(result)))
+		}
+
+		return err
+	}
+}
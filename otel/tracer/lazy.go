@@ -0,0 +1,46 @@
+package tracer
+
+import (
+	"context"
+	"sync"
+
+	sdkTrace "go.opentelemetry.io/otel/sdk/trace"
+)
+
+var _ sdkTrace.SpanExporter = (*lazyExporter)(nil)
+
+// lazyExporter builds the exporter of cfg on the first export of NewLazy.
+// A failed build fails that export and is retried by the next one.
+type lazyExporter struct {
+	cfg *Config
+
+	mu       sync.Mutex
+	exporter sdkTrace.SpanExporter
+}
+
+func (e *lazyExporter) ExportSpans(ctx context.Context, spans []sdkTrace.ReadOnlySpan) error {
+	e.mu.Lock()
+	if e.exporter == nil {
+		exporter, err := newExporter(context.WithoutCancel(ctx), e.cfg)
+		if err != nil {
+			e.mu.Unlock()
+			return err
+		}
+		e.exporter = exporter
+	}
+	exporter := e.exporter
+	e.mu.Unlock()
+
+	return exporter.ExportSpans(ctx, spans)
+}
+
+func (e *lazyExporter) Shutdown(ctx context.Context) error {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	if e.exporter == nil {
+		return nil
+	}
+
+	return e.exporter.Shutdown(ctx)
+}
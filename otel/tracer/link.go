@@ -0,0 +1,32 @@
+package tracer
+
+import (
+	"context"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// StartLinked starts a span with the tracer selected by TracerFromContext
+// that links to the span of each of linkCtxs, e.g. the messages a batch
+// consumer handles together. Contexts without a valid span context are
+// skipped. The span stays a child of the span in ctx.
+func StartLinked(ctx context.Context, name string, linkCtxs ...context.Context) (context.Context, trace.Span) {
+	links := make([]trace.Link, 0, len(linkCtxs))
+	for _, linkCtx := range linkCtxs {
+		if sc := trace.SpanContextFromContext(linkCtx); sc.IsValid() {
+			links = append(links, LinkFromSpanContext(sc))
+		}
+	}
+
+	return StartSpan(ctx, name, trace.WithLinks(links...))
+}
+
+// LinkFromSpanContext returns a link to sc carrying attrs, for
+// trace.WithLinks or trace.Span.AddLink.
+func LinkFromSpanContext(sc trace.SpanContext, attrs ...attribute.KeyValue) trace.Link {
+	return trace.Link{
+		SpanContext: sc,
+		Attributes:  attrs,
+	}
+}
@@ -0,0 +1,112 @@
+package tracer
+
+import (
+	"context"
+	"fmt"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/propagation"
+	sdkTrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+)
+
+// InMemoryTracer is the Tracer returned by InitInMemoryTracer.
+type InMemoryTracer interface {
+	Tracer
+	FlushAndReset(ctx context.Context) ([]tracetest.SpanStub, error)
+}
+
+// TestTracer is the Tracer returned by InitTestTracer.
+type TestTracer interface {
+	Tracer
+	StartedSpans() []sdkTrace.ReadWriteSpan
+	EndedSpans() []sdkTrace.ReadOnlySpan
+}
+
+// InitInMemoryTracer registers a global tracer that samples every span and
+// exports it synchronously to memory, for integration tests that share one
+// tracer across test cases. Use FlushAndReset to collect the spans.
+func InitInMemoryTracer(ctx context.Context) (InMemoryTracer, error) {
+	memory := tracetest.NewInMemoryExporter()
+	tp := sdkTrace.NewTracerProvider(
+		sdkTrace.WithSampler(sdkTrace.AlwaysSample()),
+		sdkTrace.WithSyncer(memory),
+	)
+	otel.SetTracerProvider(tp)
+	otel.SetTextMapPropagator(
+		propagation.NewCompositeTextMapPropagator(
+			propagation.TraceContext{},
+			propagation.Baggage{},
+		),
+	)
+
+	return &otelTracer{
+		tracer:         otel.Tracer("memory-tracer"),
+		tracerProvider: tp,
+		memory:         memory,
+	}, nil
+}
+
+// FlushAndReset returns the spans exported since the previous call and
+// clears them, leaving the tracer running. It fails for tracers that are
+// not created by InitInMemoryTracer.
+func (t *otelTracer) FlushAndReset(ctx context.Context) ([]tracetest.SpanStub, error) {
+	if t.memory == nil {
+		return nil, fmt.Errorf("tracer is not backed by an in-memory exporter")
+	}
+
+	if tp, ok := t.tracerProvider.(*sdkTrace.TracerProvider); ok {
+		if err := tp.ForceFlush(ctx); err != nil {
+			return nil, fmt.Errorf("failed to flush tracer provider: %w", err)
+		}
+	}
+
+	spans := t.memory.GetSpans()
+	t.memory.Reset()
+
+	return spans, nil
+}
+
+// InitTestTracer registers a global tracer that samples every span and
+// records it with a tracetest.SpanRecorder, so unit tests can assert on
+// span names and attributes through StartedSpans and EndedSpans.
+func InitTestTracer() TestTracer {
+	recorder := tracetest.NewSpanRecorder()
+	tp := sdkTrace.NewTracerProvider(
+		sdkTrace.WithSampler(sdkTrace.AlwaysSample()),
+		sdkTrace.WithSpanProcessor(recorder),
+	)
+	otel.SetTracerProvider(tp)
+	otel.SetTextMapPropagator(
+		propagation.NewCompositeTextMapPropagator(
+			propagation.TraceContext{},
+			propagation.Baggage{},
+		),
+	)
+
+	return &otelTracer{
+		tracer:         otel.Tracer("test-tracer"),
+		tracerProvider: tp,
+		recorder:       recorder,
+	}
+}
+
+// StartedSpans returns the spans started so far by a tracer created by
+// InitTestTracer, in start order. It returns nil for other tracers.
+func (t *otelTracer) StartedSpans() []sdkTrace.ReadWriteSpan {
+	if t.recorder == nil {
+		return nil
+	}
+
+	return t.recorder.Started()
+}
+
+// EndedSpans returns the spans ended so far by a tracer created by
+// InitTestTracer, in end order. It returns nil for other tracers.
+func (t *otelTracer) EndedSpans() []sdkTrace.ReadOnlySpan {
+	if t.recorder == nil {
+		return nil
+	}
+
+	return t.recorder.Ended()
+}
@@ -0,0 +1,90 @@
+package tracer
+
+import (
+	"regexp"
+
+	"go.opentelemetry.io/otel/attribute"
+	sdkTrace "go.opentelemetry.io/otel/sdk/trace"
+)
+
+// NormalizeRule rewrites the parts of span names and URL attributes matched
+// by Pattern with Replacement, which may refer to submatches as in
+// regexp.Regexp.ReplaceAllString.
+type NormalizeRule struct {
+	Pattern     *regexp.Regexp
+	Replacement string
+}
+
+// DefaultNormalizeRules collapse UUIDs, numeric IDs and long hex IDs in path
+// segments into {id}, turning "/users/123" into "/users/{id}".
+var DefaultNormalizeRules = []NormalizeRule{
+	{Pattern: regexp.MustCompile(`/[0-9a-fA-F]{8}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{12}\b`), Replacement: "/{id}"},
+	{Pattern: regexp.MustCompile(`/[0-9]+\b`), Replacement: "/{id}"},
+	{Pattern: regexp.MustCompile(`/[0-9a-fA-F]{16,}\b`), Replacement: "/{id}"},
+}
+
+// normalizedKeys are the attributes rewritten besides the span name.
+var normalizedKeys = map[attribute.Key]struct{}{
+	"http.target": {},
+	"url.path":    {},
+}
+
+// nameSpan overrides the name and attributes of an ended span.
+type nameSpan struct {
+	sdkTrace.ReadOnlySpan
+	name  string
+	attrs []attribute.KeyValue
+}
+
+func (s nameSpan) Name() string {
+	return s.name
+}
+
+func (s nameSpan) Attributes() []attribute.KeyValue {
+	return s.attrs
+}
+
+var _ sdkTrace.SpanProcessor = (*normalizeProcessor)(nil)
+
+// normalizeProcessor applies its rules, in order, to span names and to the
+// http.target and url.path attributes before passing spans on.
+type normalizeProcessor struct {
+	sdkTrace.SpanProcessor
+	rules []NormalizeRule
+}
+
+func (p *normalizeProcessor) OnEnd(s sdkTrace.ReadOnlySpan) {
+	name := p.normalize(s.Name())
+	changed := name != s.Name()
+
+	attrs := s.Attributes()
+	var copied bool
+	for i, kv := range attrs {
+		if _, ok := normalizedKeys[kv.Key]; !ok || kv.Value.Type() != attribute.STRING {
+			continue
+		}
+		value := p.normalize(kv.Value.AsString())
+		if value == kv.Value.AsString() {
+			continue
+		}
+		if !copied {
+			attrs = append([]attribute.KeyValue(nil), attrs...)
+			copied = true
+		}
+		attrs[i] = kv.Key.String(value)
+		changed = true
+	}
+
+	if changed {
+		s = nameSpan{ReadOnlySpan: s, name: name, attrs: attrs}
+	}
+	p.SpanProcessor.OnEnd(s)
+}
+
+func (p *normalizeProcessor) normalize(s string) string {
+	for _, rule := range p.rules {
+		s = rule.Pattern.ReplaceAllString(s, rule.Replacement)
+	}
+
+	return s
+}
@@ -0,0 +1,151 @@
+package tracer
+
+import (
+	"log/slog"
+	"maps"
+	"slices"
+
+	"go.opentelemetry.io/otel/attribute"
+	sdkTrace "go.opentelemetry.io/otel/sdk/trace"
+	"google.golang.org/grpc/credentials"
+)
+
+// Option configures InitTracer. A *Config is itself an Option that replaces
+// the whole configuration, so options passed after it refine it.
+type Option interface {
+	apply(cfg *Config)
+}
+
+type optionFunc func(cfg *Config)
+
+func (f optionFunc) apply(cfg *Config) {
+	f(cfg)
+}
+
+func (c *Config) apply(cfg *Config) {
+	if c != nil {
+		*cfg = *c
+	}
+}
+
+// WithEndpoint configures the collector endpoint, either a URL or a bare
+// host:port. With the gRPC protocol a unix:///path URL connects to a
+// collector listening on a Unix domain socket.
+func WithEndpoint(endpoint string) Option {
+	return optionFunc(func(cfg *Config) {
+		cfg.ExporterURL = endpoint
+	})
+}
+
+// WithServiceName configures the service.name resource attribute.
+func WithServiceName(name string) Option {
+	return optionFunc(func(cfg *Config) {
+		cfg.ServiceName = name
+	})
+}
+
+// WithServiceVersion configures the service.version resource attribute.
+func WithServiceVersion(version string) Option {
+	return optionFunc(func(cfg *Config) {
+		cfg.ServiceVersion = version
+	})
+}
+
+// WithDeploymentEnvironment configures the deployment.environment resource
+// attribute.
+func WithDeploymentEnvironment(env string) Option {
+	return optionFunc(func(cfg *Config) {
+		cfg.DeploymentEnvironment = env
+	})
+}
+
+// WithSecretToken configures the bearer token sent to the collector.
+func WithSecretToken(token string) Option {
+	return optionFunc(func(cfg *Config) {
+		cfg.SecretToken = token
+	})
+}
+
+// WithSampler configures the sampler of the tracer provider.
+func WithSampler(sampler sdkTrace.Sampler) Option {
+	return optionFunc(func(cfg *Config) {
+		cfg.Sampler = &sampler
+	})
+}
+
+// WithTLS configures the transport credentials of the gRPC exporter.
+func WithTLS(creds credentials.TransportCredentials) Option {
+	return optionFunc(func(cfg *Config) {
+		cfg.Creds = &creds
+	})
+}
+
+// WithProtocol configures the OTLP transport, ProtocolGRPC or
+// ProtocolHTTPProtobuf.
+func WithProtocol(protocol string) Option {
+	return optionFunc(func(cfg *Config) {
+		cfg.Protocol = protocol
+	})
+}
+
+// WithHeaders configures headers sent with every export.
+func WithHeaders(headers map[string]string) Option {
+	return optionFunc(func(cfg *Config) {
+		merged := make(map[string]string, len(cfg.Headers)+len(headers))
+		maps.Copy(merged, cfg.Headers)
+		maps.Copy(merged, headers)
+		cfg.Headers = merged
+	})
+}
+
+// WithHeaderProvider configures a provider of headers resolved on every
+// export request.
+func WithHeaderProvider(p HeaderProvider) Option {
+	return optionFunc(func(cfg *Config) {
+		cfg.HeaderProvider = p
+	})
+}
+
+// WithAttributes configures additional resource attributes.
+func WithAttributes(attrs ...attribute.KeyValue) Option {
+	return optionFunc(func(cfg *Config) {
+		cfg.Attributes = slices.Concat(cfg.Attributes, attrs)
+	})
+}
+
+// WithExporter adds an exporter that receives the same spans as the OTLP
+// exporter. It can be repeated.
+func WithExporter(exporter sdkTrace.SpanExporter) Option {
+	return optionFunc(func(cfg *Config) {
+		cfg.AdditionalExporters = slices.Concat(cfg.AdditionalExporters, []sdkTrace.SpanExporter{exporter})
+	})
+}
+
+// WithSpanProcessor registers p with the tracer provider alongside the
+// exporting batcher. It can be repeated.
+func WithSpanProcessor(p sdkTrace.SpanProcessor) Option {
+	return optionFunc(func(cfg *Config) {
+		cfg.SpanProcessors = slices.Concat(cfg.SpanProcessors, []sdkTrace.SpanProcessor{p})
+	})
+}
+
+// WithIDGenerator configures the generator of trace and span IDs.
+func WithIDGenerator(g sdkTrace.IDGenerator) Option {
+	return optionFunc(func(cfg *Config) {
+		cfg.IDGenerator = g
+	})
+}
+
+// WithLogger configures the logger used for warnings.
+func WithLogger(logger *slog.Logger) Option {
+	return optionFunc(func(cfg *Config) {
+		cfg.Logger = logger
+	})
+}
+
+// WithRetry configures how failed exports are retried.
+func WithRetry(retry RetryConfig) Option {
+	return optionFunc(func(cfg *Config) {
+		cfg.Retry = &retry
+	})
+}
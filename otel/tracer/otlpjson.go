@@ -0,0 +1,450 @@
+package tracer
+
+import (
+	"fmt"
+	"strconv"
+	"time"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/sdk/instrumentation"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdkTrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// The types below follow the OTLP/JSON encoding of
+// ExportTraceServiceRequest: camelCase field names, hex trace and span IDs,
+// enums as integers and 64-bit integers as strings.
+
+type otlpTraces struct {
+	ResourceSpans []*otlpResourceSpans `json:"resourceSpans"`
+}
+
+type otlpResourceSpans struct {
+	Resource   otlpResource      `json:"resource"`
+	ScopeSpans []*otlpScopeSpans `json:"scopeSpans"`
+	SchemaURL  string            `json:"schemaUrl,omitempty"`
+}
+
+type otlpResource struct {
+	Attributes []otlpKeyValue `json:"attributes,omitempty"`
+}
+
+type otlpScopeSpans struct {
+	Scope     otlpScope  `json:"scope"`
+	Spans     []otlpSpan `json:"spans"`
+	SchemaURL string     `json:"schemaUrl,omitempty"`
+}
+
+type otlpScope struct {
+	Name       string         `json:"name,omitempty"`
+	Version    string         `json:"version,omitempty"`
+	Attributes []otlpKeyValue `json:"attributes,omitempty"`
+}
+
+type otlpSpan struct {
+	TraceID                string         `json:"traceId"`
+	SpanID                 string         `json:"spanId"`
+	TraceState             string         `json:"traceState,omitempty"`
+	ParentSpanID           string         `json:"parentSpanId,omitempty"`
+	Flags                  uint32         `json:"flags,omitempty"`
+	Name                   string         `json:"name"`
+	Kind                   int            `json:"kind"`
+	StartTimeUnixNano      string         `json:"startTimeUnixNano"`
+	EndTimeUnixNano        string         `json:"endTimeUnixNano"`
+	Attributes             []otlpKeyValue `json:"attributes,omitempty"`
+	DroppedAttributesCount int            `json:"droppedAttributesCount,omitempty"`
+	Events                 []otlpEvent    `json:"events,omitempty"`
+	DroppedEventsCount     int            `json:"droppedEventsCount,omitempty"`
+	Links                  []otlpLink     `json:"links,omitempty"`
+	DroppedLinksCount      int            `json:"droppedLinksCount,omitempty"`
+	Status                 otlpStatus     `json:"status"`
+}
+
+type otlpEvent struct {
+	TimeUnixNano           string         `json:"timeUnixNano"`
+	Name                   string         `json:"name"`
+	Attributes             []otlpKeyValue `json:"attributes,omitempty"`
+	DroppedAttributesCount int            `json:"droppedAttributesCount,omitempty"`
+}
+
+type otlpLink struct {
+	TraceID                string         `json:"traceId"`
+	SpanID                 string         `json:"spanId"`
+	TraceState             string         `json:"traceState,omitempty"`
+	Attributes             []otlpKeyValue `json:"attributes,omitempty"`
+	DroppedAttributesCount int            `json:"droppedAttributesCount,omitempty"`
+	Flags                  uint32         `json:"flags,omitempty"`
+}
+
+type otlpStatus struct {
+	Message string `json:"message,omitempty"`
+	Code    int    `json:"code,omitempty"`
+}
+
+type otlpKeyValue struct {
+	Key   string       `json:"key"`
+	Value otlpAnyValue `json:"value"`
+}
+
+type otlpAnyValue struct {
+	StringValue *string         `json:"stringValue,omitempty"`
+	BoolValue   *bool           `json:"boolValue,omitempty"`
+	IntValue    *string         `json:"intValue,omitempty"`
+	DoubleValue *float64        `json:"doubleValue,omitempty"`
+	ArrayValue  *otlpArrayValue `json:"arrayValue,omitempty"`
+}
+
+type otlpArrayValue struct {
+	Values []otlpAnyValue `json:"values"`
+}
+
+// otlpJSONTraces groups spans by resource and instrumentation scope.
+func otlpJSONTraces(spans []sdkTrace.ReadOnlySpan) otlpTraces {
+	type scopeKey struct {
+		res   *resource.Resource
+		scope instrumentation.Scope
+	}
+
+	var out otlpTraces
+	resources := make(map[*resource.Resource]*otlpResourceSpans)
+	scopes := make(map[scopeKey]*otlpScopeSpans)
+
+	for _, s := range spans {
+		res := s.Resource()
+		rs, ok := resources[res]
+		if !ok {
+			rs = &otlpResourceSpans{
+				Resource:  otlpResource{Attributes: otlpAttributes(res.Attributes())},
+				SchemaURL: res.SchemaURL(),
+			}
+			resources[res] = rs
+			out.ResourceSpans = append(out.ResourceSpans, rs)
+		}
+
+		scope := s.InstrumentationScope()
+		key := scopeKey{res: res, scope: scope}
+		ss, ok := scopes[key]
+		if !ok {
+			ss = &otlpScopeSpans{
+				Scope: otlpScope{
+					Name:       scope.Name,
+					Version:    scope.Version,
+					Attributes: otlpAttributes(scope.Attributes.ToSlice()),
+				},
+				SchemaURL: scope.SchemaURL,
+			}
+			scopes[key] = ss
+			rs.ScopeSpans = append(rs.ScopeSpans, ss)
+		}
+
+		ss.Spans = append(ss.Spans, otlpJSONSpan(s))
+	}
+
+	return out
+}
+
+func otlpJSONSpan(s sdkTrace.ReadOnlySpan) otlpSpan {
+	sc := s.SpanContext()
+	span := otlpSpan{
+		TraceID:                sc.TraceID().String(),
+		SpanID:                 sc.SpanID().String(),
+		TraceState:             sc.TraceState().String(),
+		Flags:                  uint32(sc.TraceFlags()),
+		Name:                   s.Name(),
+		Kind:                   otlpSpanKind(s.SpanKind()),
+		StartTimeUnixNano:      strconv.FormatInt(s.StartTime().UnixNano(), 10),
+		EndTimeUnixNano:        strconv.FormatInt(s.EndTime().UnixNano(), 10),
+		Attributes:             otlpAttributes(s.Attributes()),
+		DroppedAttributesCount: s.DroppedAttributes(),
+		DroppedEventsCount:     s.DroppedEvents(),
+		DroppedLinksCount:      s.DroppedLinks(),
+		Status:                 otlpStatus{Message: s.Status().Description, Code: otlpStatusCode(s.Status().Code)},
+	}
+	if parent := s.Parent(); parent.HasSpanID() {
+		span.ParentSpanID = parent.SpanID().String()
+	}
+
+	for _, e := range s.Events() {
+		span.Events = append(span.Events, otlpEvent{
+			TimeUnixNano:           strconv.FormatInt(e.Time.UnixNano(), 10),
+			Name:                   e.Name,
+			Attributes:             otlpAttributes(e.Attributes),
+			DroppedAttributesCount: e.DroppedAttributeCount,
+		})
+	}
+	for _, l := range s.Links() {
+		span.Links = append(span.Links, otlpLink{
+			TraceID:                l.SpanContext.TraceID().String(),
+			SpanID:                 l.SpanContext.SpanID().String(),
+			TraceState:             l.SpanContext.TraceState().String(),
+			Attributes:             otlpAttributes(l.Attributes),
+			DroppedAttributesCount: l.DroppedAttributeCount,
+			Flags:                  uint32(l.SpanContext.TraceFlags()),
+		})
+	}
+
+	return span
+}
+
+// otlpSpanKind maps to the OTLP SpanKind enum, which numbers the kinds as
+// trace.SpanKind does.
+func otlpSpanKind(kind trace.SpanKind) int {
+	if kind < trace.SpanKindInternal || kind > trace.SpanKindConsumer {
+		return int(trace.SpanKindInternal)
+	}
+
+	return int(kind)
+}
+
+// otlpStatusCode maps to the OTLP StatusCode enum: unset 0, ok 1, error 2.
+func otlpStatusCode(code codes.Code) int {
+	switch code {
+	case codes.Ok:
+		return 1
+	case codes.Error:
+		return 2
+	default:
+		return 0
+	}
+}
+
+func otlpAttributes(attrs []attribute.KeyValue) []otlpKeyValue {
+	if len(attrs) == 0 {
+		return nil
+	}
+
+	out := make([]otlpKeyValue, 0, len(attrs))
+	for _, kv := range attrs {
+		out = append(out, otlpKeyValue{Key: string(kv.Key), Value: otlpValue(kv.Value)})
+	}
+
+	return out
+}
+
+func otlpValue(v attribute.Value) otlpAnyValue {
+	switch v.Type() {
+	case attribute.BOOL:
+		b := v.AsBool()
+		return otlpAnyValue{BoolValue: &b}
+	case attribute.INT64:
+		i := strconv.FormatInt(v.AsInt64(), 10)
+		return otlpAnyValue{IntValue: &i}
+	case attribute.FLOAT64:
+		f := v.AsFloat64()
+		return otlpAnyValue{DoubleValue: &f}
+	case attribute.BOOLSLICE:
+		return otlpArray(v.AsBoolSlice(), attribute.BoolValue)
+	case attribute.INT64SLICE:
+		return otlpArray(v.AsInt64Slice(), attribute.Int64Value)
+	case attribute.FLOAT64SLICE:
+		return otlpArray(v.AsFloat64Slice(), attribute.Float64Value)
+	case attribute.STRINGSLICE:
+		return otlpArray(v.AsStringSlice(), attribute.StringValue)
+	default:
+		s := v.Emit()
+		return otlpAnyValue{StringValue: &s}
+	}
+}
+
+func otlpArray[T any](values []T, value func(T) attribute.Value) otlpAnyValue {
+	array := &otlpArrayValue{Values: make([]otlpAnyValue, 0, len(values))}
+	for _, v := range values {
+		array.Values = append(array.Values, otlpValue(value(v)))
+	}
+
+	return otlpAnyValue{ArrayValue: array}
+}
+
+// spansFromOTLPJSON is the inverse of otlpJSONTraces, rebuilding the spans
+// so they can be exported again.
+func spansFromOTLPJSON(traces otlpTraces) ([]sdkTrace.ReadOnlySpan, error) {
+	var spans tracetest.SpanStubs
+	for _, rs := range traces.ResourceSpans {
+		res := resource.NewWithAttributes(rs.SchemaURL, otlpAttributesFromJSON(rs.Resource.Attributes)...)
+		for _, ss := range rs.ScopeSpans {
+			scope := instrumentation.Scope{
+				Name:       ss.Scope.Name,
+				Version:    ss.Scope.Version,
+				SchemaURL:  ss.SchemaURL,
+				Attributes: attribute.NewSet(otlpAttributesFromJSON(ss.Scope.Attributes)...),
+			}
+			for _, s := range ss.Spans {
+				span, err := otlpSpanFromJSON(s)
+				if err != nil {
+					return nil, err
+				}
+				span.Resource = res
+				span.InstrumentationScope = scope
+				spans = append(spans, span)
+			}
+		}
+	}
+
+	return spans.Snapshots(), nil
+}
+
+func otlpSpanFromJSON(s otlpSpan) (tracetest.SpanStub, error) {
+	sc, err := otlpSpanContextFromJSON(s.TraceID, s.SpanID, s.TraceState, s.Flags)
+	if err != nil {
+		return tracetest.SpanStub{}, err
+	}
+	start, err := otlpTimeFromJSON(s.StartTimeUnixNano)
+	if err != nil {
+		return tracetest.SpanStub{}, err
+	}
+	end, err := otlpTimeFromJSON(s.EndTimeUnixNano)
+	if err != nil {
+		return tracetest.SpanStub{}, err
+	}
+
+	span := tracetest.SpanStub{
+		Name:              s.Name,
+		SpanContext:       sc,
+		SpanKind:          trace.SpanKind(s.Kind),
+		StartTime:         start,
+		EndTime:           end,
+		Attributes:        otlpAttributesFromJSON(s.Attributes),
+		Status:            sdkTrace.Status{Code: otlpStatusCodeFromJSON(s.Status.Code), Description: s.Status.Message},
+		DroppedAttributes: s.DroppedAttributesCount,
+		DroppedEvents:     s.DroppedEventsCount,
+		DroppedLinks:      s.DroppedLinksCount,
+	}
+	if s.ParentSpanID != "" {
+		parentID, err := trace.SpanIDFromHex(s.ParentSpanID)
+		if err != nil {
+			return tracetest.SpanStub{}, fmt.Errorf("invalid parent span id: %w", err)
+		}
+		span.Parent = trace.NewSpanContext(trace.SpanContextConfig{TraceID: sc.TraceID(), SpanID: parentID})
+	}
+
+	for _, e := range s.Events {
+		t, err := otlpTimeFromJSON(e.TimeUnixNano)
+		if err != nil {
+			return tracetest.SpanStub{}, err
+		}
+		span.Events = append(span.Events, sdkTrace.Event{
+			Name:                  e.Name,
+			Attributes:            otlpAttributesFromJSON(e.Attributes),
+			DroppedAttributeCount: e.DroppedAttributesCount,
+			Time:                  t,
+		})
+	}
+	for _, l := range s.Links {
+		linked, err := otlpSpanContextFromJSON(l.TraceID, l.SpanID, l.TraceState, l.Flags)
+		if err != nil {
+			return tracetest.SpanStub{}, err
+		}
+		span.Links = append(span.Links, sdkTrace.Link{
+			SpanContext:           linked,
+			Attributes:            otlpAttributesFromJSON(l.Attributes),
+			DroppedAttributeCount: l.DroppedAttributesCount,
+		})
+	}
+
+	return span, nil
+}
+
+func otlpSpanContextFromJSON(traceID, spanID, traceState string, flags uint32) (trace.SpanContext, error) {
+	tid, err := trace.TraceIDFromHex(traceID)
+	if err != nil {
+		return trace.SpanContext{}, fmt.Errorf("invalid trace id: %w", err)
+	}
+	sid, err := trace.SpanIDFromHex(spanID)
+	if err != nil {
+		return trace.SpanContext{}, fmt.Errorf("invalid span id: %w", err)
+	}
+	ts, err := trace.ParseTraceState(traceState)
+	if err != nil {
+		return trace.SpanContext{}, fmt.Errorf("invalid trace state: %w", err)
+	}
+
+	return trace.NewSpanContext(trace.SpanContextConfig{
+		TraceID:    tid,
+		SpanID:     sid,
+		TraceFlags: trace.TraceFlags(flags),
+		TraceState: ts,
+	}), nil
+}
+
+func otlpTimeFromJSON(unixNano string) (time.Time, error) {
+	n, err := strconv.ParseInt(unixNano, 10, 64)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("invalid timestamp: %w", err)
+	}
+
+	return time.Unix(0, n), nil
+}
+
+func otlpStatusCodeFromJSON(code int) codes.Code {
+	switch code {
+	case 1:
+		return codes.Ok
+	case 2:
+		return codes.Error
+	default:
+		return codes.Unset
+	}
+}
+
+func otlpAttributesFromJSON(kvs []otlpKeyValue) []attribute.KeyValue {
+	if len(kvs) == 0 {
+		return nil
+	}
+
+	attrs := make([]attribute.KeyValue, 0, len(kvs))
+	for _, kv := range kvs {
+		attrs = append(attrs, attribute.KeyValue{Key: attribute.Key(kv.Key), Value: otlpValueFromJSON(kv.Value)})
+	}
+
+	return attrs
+}
+
+// otlpValueFromJSON decodes the values written by otlpValue. Arrays take
+// the type of their first element.
+func otlpValueFromJSON(v otlpAnyValue) attribute.Value {
+	switch {
+	case v.BoolValue != nil:
+		return attribute.BoolValue(*v.BoolValue)
+	case v.IntValue != nil:
+		i, _ := strconv.ParseInt(*v.IntValue, 10, 64)
+		return attribute.Int64Value(i)
+	case v.DoubleValue != nil:
+		return attribute.Float64Value(*v.DoubleValue)
+	case v.ArrayValue != nil:
+		return otlpArrayFromJSON(v.ArrayValue.Values)
+	case v.StringValue != nil:
+		return attribute.StringValue(*v.StringValue)
+	default:
+		return attribute.StringValue("")
+	}
+}
+
+func otlpArrayFromJSON(values []otlpAnyValue) attribute.Value {
+	if len(values) == 0 {
+		return attribute.StringSliceValue(nil)
+	}
+
+	first := otlpValueFromJSON(values[0])
+	switch first.Type() {
+	case attribute.BOOL:
+		return attribute.BoolSliceValue(otlpSliceFromJSON(values, attribute.Value.AsBool))
+	case attribute.INT64:
+		return attribute.Int64SliceValue(otlpSliceFromJSON(values, attribute.Value.AsInt64))
+	case attribute.FLOAT64:
+		return attribute.Float64SliceValue(otlpSliceFromJSON(values, attribute.Value.AsFloat64))
+	default:
+		return attribute.StringSliceValue(otlpSliceFromJSON(values, attribute.Value.AsString))
+	}
+}
+
+func otlpSliceFromJSON[T any](values []otlpAnyValue, as func(attribute.Value) T) []T {
+	out := make([]T, 0, len(values))
+	for _, v := range values {
+		out = append(out, as(otlpValueFromJSON(v)))
+	}
+
+	return out
+}
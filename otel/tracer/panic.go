@@ -0,0 +1,38 @@
+package tracer
+
+import (
+	"context"
+	"fmt"
+
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// RecoverAndRecord records a panic on the span in ctx and panics again
+// with the same value. It must be deferred directly, after the span is
+// started so that it runs before the span ends:
+//
+//	ctx, span := tracer.Start(ctx, "work")
+//	defer span.End()
+//	defer tracer.RecoverAndRecord(ctx)
+func RecoverAndRecord(ctx context.Context) {
+	if r := recover(); r != nil {
+		RecordPanic(ctx, r)
+		panic(r)
+	}
+}
+
+// RecordPanic records a recovered panic value on the span in ctx as an
+// exception event with the stack trace and sets the span status to Error.
+// Call it from a deferred function, where the stack still includes the
+// panicking frames.
+func RecordPanic(ctx context.Context, r any) {
+	err, ok := r.(error)
+	if !ok {
+		err = fmt.Errorf("%v", r)
+	}
+
+	span := trace.SpanFromContext(ctx)
+	span.RecordError(err, trace.WithStackTrace(true), trace.WithAttributes(panicKey.Bool(true)))
+	span.SetStatus(codes.Error, fmt.Sprintf("panic: %v", r))
+}
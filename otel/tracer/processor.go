@@ -0,0 +1,266 @@
+package tracer
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"sync"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	sdkTrace "go.opentelemetry.io/otel/sdk/trace"
+)
+
+// attributesSpan overrides the attributes of an ended span. Ended spans are
+// read-only, so processors that rewrite attributes hand this view to the
+// next processor instead.
+type attributesSpan struct {
+	sdkTrace.ReadOnlySpan
+	attrs []attribute.KeyValue
+}
+
+func (s attributesSpan) Attributes() []attribute.KeyValue {
+	return s.attrs
+}
+
+var _ sdkTrace.SpanProcessor = (fanOutProcessor)(nil)
+
+// fanOutProcessor hands every span to each of its processors, so the
+// processors wrapping it filter and rewrite spans once for all exporters.
+type fanOutProcessor []sdkTrace.SpanProcessor
+
+func (p fanOutProcessor) OnStart(parent context.Context, s sdkTrace.ReadWriteSpan) {
+	for _, sp := range p {
+		sp.OnStart(parent, s)
+	}
+}
+
+func (p fanOutProcessor) OnEnd(s sdkTrace.ReadOnlySpan) {
+	for _, sp := range p {
+		sp.OnEnd(s)
+	}
+}
+
+func (p fanOutProcessor) Shutdown(ctx context.Context) error {
+	var errs []error
+	for _, sp := range p {
+		errs = append(errs, sp.Shutdown(ctx))
+	}
+
+	return errors.Join(errs...)
+}
+
+func (p fanOutProcessor) ForceFlush(ctx context.Context) error {
+	var errs []error
+	for _, sp := range p {
+		errs = append(errs, sp.ForceFlush(ctx))
+	}
+
+	return errors.Join(errs...)
+}
+
+var _ sdkTrace.SpanProcessor = (*filterProcessor)(nil)
+
+// filterProcessor keeps spans matched by any of its drop rules from reaching
+// the next processor.
+type filterProcessor struct {
+	sdkTrace.SpanProcessor
+	rules []func(s sdkTrace.ReadOnlySpan) bool
+}
+
+func (p *filterProcessor) OnEnd(s sdkTrace.ReadOnlySpan) {
+	for _, drop := range p.rules {
+		if drop(s) {
+			return
+		}
+	}
+	p.SpanProcessor.OnEnd(s)
+}
+
+// statusSpan overrides the status of an ended span.
+type statusSpan struct {
+	sdkTrace.ReadOnlySpan
+	status sdkTrace.Status
+}
+
+func (s statusSpan) Status() sdkTrace.Status {
+	return s.status
+}
+
+var _ sdkTrace.SpanProcessor = (*okStatusProcessor)(nil)
+
+// okStatusProcessor reports spans that ended with an unset status as OK.
+// Spans with an explicit status, error or otherwise, are left as they are.
+type okStatusProcessor struct {
+	sdkTrace.SpanProcessor
+}
+
+func (p *okStatusProcessor) OnEnd(s sdkTrace.ReadOnlySpan) {
+	if s.Status().Code == codes.Unset {
+		s = statusSpan{ReadOnlySpan: s, status: sdkTrace.Status{Code: codes.Ok}}
+	}
+	p.SpanProcessor.OnEnd(s)
+}
+
+var _ sdkTrace.SpanProcessor = (*cardinalityProcessor)(nil)
+
+// cardinalityProcessor tracks the distinct values seen per attribute key and
+// drops values of keys that exceeded the limit before passing spans on.
+// Only sampled spans count, those the sampler dropped are not exported and
+// must not use up the limit.
+type cardinalityProcessor struct {
+	sdkTrace.SpanProcessor
+	limit  int
+	logger *slog.Logger
+
+	mu       sync.Mutex
+	seen     map[attribute.Key]map[string]struct{}
+	exceeded map[attribute.Key]struct{}
+}
+
+func newCardinalityProcessor(next sdkTrace.SpanProcessor, limit int, logger *slog.Logger) *cardinalityProcessor {
+	return &cardinalityProcessor{
+		SpanProcessor: next,
+		limit:         limit,
+		logger:        logger,
+		seen:          make(map[attribute.Key]map[string]struct{}),
+		exceeded:      make(map[attribute.Key]struct{}),
+	}
+}
+
+func (p *cardinalityProcessor) OnEnd(s sdkTrace.ReadOnlySpan) {
+	if !s.SpanContext().IsSampled() {
+		p.SpanProcessor.OnEnd(s)
+		return
+	}
+
+	attrs := s.Attributes()
+
+	var kept []attribute.KeyValue
+	for i, kv := range attrs {
+		if p.allow(kv) {
+			if kept != nil {
+				kept = append(kept, kv)
+			}
+			continue
+		}
+		if kept == nil {
+			kept = make([]attribute.KeyValue, i, len(attrs))
+			copy(kept, attrs[:i])
+		}
+	}
+
+	if kept != nil {
+		s = attributesSpan{ReadOnlySpan: s, attrs: kept}
+	}
+	p.SpanProcessor.OnEnd(s)
+}
+
+func (p *cardinalityProcessor) allow(kv attribute.KeyValue) bool {
+	value := kv.Value.Emit()
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	values, ok := p.seen[kv.Key]
+	if !ok {
+		values = make(map[string]struct{})
+		p.seen[kv.Key] = values
+	}
+	if _, ok := values[value]; ok {
+		return true
+	}
+	if len(values) < p.limit {
+		values[value] = struct{}{}
+		return true
+	}
+
+	if _, ok := p.exceeded[kv.Key]; !ok {
+		p.exceeded[kv.Key] = struct{}{}
+		p.logger.Warn("span attribute exceeded cardinality limit, dropping new values",
+			slog.String("key", string(kv.Key)),
+			slog.Int("limit", p.limit),
+		)
+	}
+
+	return false
+}
+
+var _ sdkTrace.SpanProcessor = (*eventCountProcessor)(nil)
+
+// eventCountProcessor warns, once per span name, about spans that ended
+// with more events than the limit.
+type eventCountProcessor struct {
+	limit  int
+	logger *slog.Logger
+	warned sync.Map
+}
+
+func (p *eventCountProcessor) OnStart(parent context.Context, s sdkTrace.ReadWriteSpan) {}
+
+func (p *eventCountProcessor) OnEnd(s sdkTrace.ReadOnlySpan) {
+	count := len(s.Events()) + s.DroppedEvents()
+	if count <= p.limit {
+		return
+	}
+
+	if _, loaded := p.warned.LoadOrStore(s.Name(), struct{}{}); loaded {
+		return
+	}
+	p.logger.Warn("span has too many events, check for AddEvent calls in a loop",
+		slog.String("span", s.Name()),
+		slog.Int("events", count),
+		slog.Int("limit", p.limit),
+	)
+}
+
+func (p *eventCountProcessor) Shutdown(ctx context.Context) error {
+	return nil
+}
+
+func (p *eventCountProcessor) ForceFlush(ctx context.Context) error {
+	return nil
+}
+
+var _ sdkTrace.SpanProcessor = (*debugProcessor)(nil)
+
+// debugProcessor logs every sampled span as it is handed to the exporters.
+type debugProcessor struct {
+	logger *slog.Logger
+}
+
+func (p *debugProcessor) OnStart(parent context.Context, s sdkTrace.ReadWriteSpan) {}
+
+func (p *debugProcessor) OnEnd(s sdkTrace.ReadOnlySpan) {
+	if !s.SpanContext().IsSampled() {
+		return
+	}
+
+	attrs := make([]any, 0, len(s.Attributes()))
+	for _, kv := range s.Attributes() {
+		attrs = append(attrs, slog.Any(string(kv.Key), kv.Value.AsInterface()))
+	}
+
+	args := []any{
+		slog.String("span", s.Name()),
+		slog.String("kind", s.SpanKind().String()),
+		slog.String("trace_id", s.SpanContext().TraceID().String()),
+		slog.String("span_id", s.SpanContext().SpanID().String()),
+		slog.Duration("duration", s.EndTime().Sub(s.StartTime())),
+		slog.String("status", s.Status().Code.String()),
+	}
+	if s.Status().Description != "" {
+		args = append(args, slog.String("status_description", s.Status().Description))
+	}
+	args = append(args, slog.Group("attributes", attrs...))
+
+	p.logger.Info("span exported", args...)
+}
+
+func (p *debugProcessor) Shutdown(ctx context.Context) error {
+	return nil
+}
+
+func (p *debugProcessor) ForceFlush(ctx context.Context) error {
+	return nil
+}
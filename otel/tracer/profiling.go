@@ -0,0 +1,63 @@
+package tracer
+
+import (
+	"context"
+	"runtime/pprof"
+	"sync"
+
+	sdkTrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// Profile label keys set by Config.ProfilingLabels. Pyroscope reads pprof
+// labels of Go programs as its own, so span_id and span_name are the keys
+// its trace to profile links look up.
+const (
+	traceIDLabel  = "trace_id"
+	spanIDLabel   = "span_id"
+	spanNameLabel = "span_name"
+)
+
+// profilingProviders holds the providers of the tracers created with
+// Config.ProfilingLabels, so that each tracer decides for its own spans.
+var profilingProviders sync.Map
+
+// withProfilingLabels sets pprof labels identifying span on the current
+// goroutine and returns the labeled context along with span, wrapped to
+// restore the labels of parent when it ends. Unsampled spans are left
+// alone, their profiles could not be matched with an exported trace, and
+// so are the spans of tracers without Config.ProfilingLabels.
+func withProfilingLabels(parent, ctx context.Context, name string, span trace.Span) (context.Context, trace.Span) {
+	sc := span.SpanContext()
+	if !sc.IsSampled() {
+		return ctx, span
+	}
+	tp, ok := span.TracerProvider().(*sdkTrace.TracerProvider)
+	if !ok {
+		return ctx, span
+	}
+	if _, ok := profilingProviders.Load(tp); !ok {
+		return ctx, span
+	}
+
+	ctx = pprof.WithLabels(ctx, pprof.Labels(
+		traceIDLabel, sc.TraceID().String(),
+		spanIDLabel, sc.SpanID().String(),
+		spanNameLabel, name,
+	))
+	pprof.SetGoroutineLabels(ctx)
+
+	return ctx, &labeledSpan{Span: span, parent: parent}
+}
+
+// labeledSpan puts back the profile labels of the context its span was
+// started from when the span ends.
+type labeledSpan struct {
+	trace.Span
+	parent context.Context
+}
+
+func (s *labeledSpan) End(opts ...trace.SpanEndOption) {
+	s.Span.End(opts...)
+	pprof.SetGoroutineLabels(s.parent)
+}
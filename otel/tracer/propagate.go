@@ -0,0 +1,49 @@
+package tracer
+
+import (
+	"net/http"
+
+	"go.opentelemetry.io/otel/propagation"
+)
+
+// NewPropagationHandler extracts the incoming trace context into the
+// request context without starting a span. The IDs are then available to
+// SpanContextFields, and NewPropagationTransport forwards them unchanged,
+// which suits gateways and proxies that log but do not trace. The context
+// is extracted by the propagator of t, or the global one when t is nil.
+func NewPropagationHandler(t Tracer, next http.Handler) http.Handler {
+	propagator := propagatorOf(t)
+
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ctx := propagator.Extract(r.Context(), propagation.HeaderCarrier(r.Header))
+		next.ServeHTTP(w, r.WithContext(ctx))
+	})
+}
+
+var _ http.RoundTripper = (*propagationTransport)(nil)
+
+type propagationTransport struct {
+	propagator propagation.TextMapPropagator
+	base       http.RoundTripper
+}
+
+// NewPropagationTransport injects the trace context of the request context
+// into outgoing requests without starting a span, using the propagator of
+// t, or the global one when t is nil. A nil base uses http.DefaultTransport.
+func NewPropagationTransport(t Tracer, base http.RoundTripper) http.RoundTripper {
+	if base == nil {
+		base = http.DefaultTransport
+	}
+
+	return &propagationTransport{
+		propagator: propagatorOf(t),
+		base:       base,
+	}
+}
+
+func (t *propagationTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	req = req.Clone(req.Context())
+	t.propagator.Inject(req.Context(), propagation.HeaderCarrier(req.Header))
+
+	return t.base.RoundTrip(req)
+}
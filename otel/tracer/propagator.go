@@ -0,0 +1,59 @@
+package tracer
+
+import (
+	"fmt"
+	"slices"
+
+	"go.opentelemetry.io/contrib/propagators/aws/xray"
+	"go.opentelemetry.io/contrib/propagators/b3"
+	"go.opentelemetry.io/contrib/propagators/jaeger"
+	"go.opentelemetry.io/otel/propagation"
+)
+
+var defaultPropagators = []string{"tracecontext", "baggage"}
+
+// resolvePropagators returns the propagator names of cfg, with the default
+// ones when none are configured and xray added for XRayCompatible.
+func resolvePropagators(cfg *Config) []string {
+	names := cfg.Propagators
+	if len(names) == 0 {
+		names = defaultPropagators
+	}
+	if cfg.XRayCompatible && !slices.Contains(names, "xray") {
+		names = slices.Concat(names, []string{"xray"})
+	}
+
+	return names
+}
+
+// newPropagator composes the propagators named in the OTEL_PROPAGATORS
+// vocabulary, defaulting to W3C trace context and baggage. "none" adds
+// nothing.
+func newPropagator(names []string) (propagation.TextMapPropagator, error) {
+	if len(names) == 0 {
+		names = defaultPropagators
+	}
+
+	propagators := make([]propagation.TextMapPropagator, 0, len(names))
+	for _, name := range names {
+		switch name {
+		case "none":
+		case "tracecontext":
+			propagators = append(propagators, propagation.TraceContext{})
+		case "baggage":
+			propagators = append(propagators, propagation.Baggage{})
+		case "b3":
+			propagators = append(propagators, b3.New(b3.WithInjectEncoding(b3.B3SingleHeader)))
+		case "b3multi":
+			propagators = append(propagators, b3.New(b3.WithInjectEncoding(b3.B3MultipleHeader)))
+		case "jaeger":
+			propagators = append(propagators, jaeger.Jaeger{})
+		case "xray":
+			propagators = append(propagators, xray.Propagator{})
+		default:
+			return nil, fmt.Errorf("unsupported propagator %q", name)
+		}
+	}
+
+	return propagation.NewCompositeTextMapPropagator(propagators...), nil
+}
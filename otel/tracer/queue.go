@@ -0,0 +1,137 @@
+package tracer
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	sdkTrace "go.opentelemetry.io/otel/sdk/trace"
+)
+
+const defaultQueueDepthInterval = 10 * time.Second
+
+// queueDepth tracks the number of spans waiting in the batch span processor
+// from the spans handed to it and the spans that reached the exporter.
+// queueDepthProcessor enforces maxSize itself and counts the spans it drops,
+// since the batch span processor drops silently; the batch span processor
+// is given room for an extra batch so it never drops first.
+type queueDepth struct {
+	enqueued atomic.Int64
+	dequeued atomic.Int64
+	dropped  atomic.Int64
+	maxSize  int64
+}
+
+func (q *queueDepth) depth() int {
+	d := q.enqueued.Load() - q.dequeued.Load()
+	if d < 0 {
+		return 0
+	}
+	if d > q.maxSize {
+		return int(q.maxSize)
+	}
+
+	return int(d)
+}
+
+var _ sdkTrace.SpanProcessor = (*queueDepthProcessor)(nil)
+
+// queueDepthProcessor counts spans handed to the batch span processor,
+// drops spans once the queue is full, or hands them to overflow when set,
+// and reports the queue depth and the spans dropped since the last report
+// on every interval until shutdown.
+type queueDepthProcessor struct {
+	sdkTrace.SpanProcessor
+	queue    *queueDepth
+	overflow func(sdkTrace.ReadOnlySpan)
+
+	stop     chan struct{}
+	stopOnce sync.Once
+	done     chan struct{}
+}
+
+func newQueueDepthProcessor(next sdkTrace.SpanProcessor, queue *queueDepth, interval time.Duration, reportDepth func(depth int), reportDropped func(count int)) *queueDepthProcessor {
+	if interval <= 0 {
+		interval = defaultQueueDepthInterval
+	}
+
+	p := &queueDepthProcessor{
+		SpanProcessor: next,
+		queue:         queue,
+		stop:          make(chan struct{}),
+		done:          make(chan struct{}),
+	}
+
+	go func() {
+		defer close(p.done)
+
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		var reported int64
+		report := func() {
+			if reportDepth != nil {
+				reportDepth(queue.depth())
+			}
+			if dropped := queue.dropped.Load(); reportDropped != nil && dropped > reported {
+				reportDropped(int(dropped - reported))
+				reported = dropped
+			}
+		}
+
+		for {
+			select {
+			case <-ticker.C:
+				report()
+			case <-p.stop:
+				report()
+				return
+			}
+		}
+	}()
+
+	return p
+}
+
+func (p *queueDepthProcessor) OnEnd(s sdkTrace.ReadOnlySpan) {
+	if s.SpanContext().IsSampled() {
+		if p.queue.enqueued.Load()-p.queue.dequeued.Load() >= p.queue.maxSize {
+			if p.overflow != nil {
+				p.overflow(s)
+				return
+			}
+			p.queue.dropped.Add(1)
+			return
+		}
+		p.queue.enqueued.Add(1)
+	}
+	p.SpanProcessor.OnEnd(s)
+}
+
+func (p *queueDepthProcessor) Shutdown(ctx context.Context) error {
+	// Spans still queued are exported by the shutdown of the batch span
+	// processor, so it runs before the final report.
+	err := p.SpanProcessor.Shutdown(ctx)
+
+	p.stopOnce.Do(func() {
+		close(p.stop)
+	})
+	<-p.done
+
+	return err
+}
+
+var _ sdkTrace.SpanExporter = (*queueDepthExporter)(nil)
+
+// queueDepthExporter counts the spans that left the batch span processor.
+type queueDepthExporter struct {
+	sdkTrace.SpanExporter
+	queue *queueDepth
+}
+
+func (e *queueDepthExporter) ExportSpans(ctx context.Context, spans []sdkTrace.ReadOnlySpan) error {
+	e.queue.dequeued.Add(int64(len(spans)))
+
+	return e.SpanExporter.ExportSpans(ctx, spans)
+}
@@ -0,0 +1,133 @@
+package tracer
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"path"
+	"strings"
+
+	"go.opentelemetry.io/otel/attribute"
+	sdkTrace "go.opentelemetry.io/otel/sdk/trace"
+)
+
+// redactedSpan overrides the attributes and events of an ended span.
+type redactedSpan struct {
+	sdkTrace.ReadOnlySpan
+	attrs  []attribute.KeyValue
+	events []sdkTrace.Event
+}
+
+func (s redactedSpan) Attributes() []attribute.KeyValue {
+	return s.attrs
+}
+
+func (s redactedSpan) Events() []sdkTrace.Event {
+	return s.events
+}
+
+var _ sdkTrace.SpanProcessor = (*redactProcessor)(nil)
+
+// redactProcessor drops or hashes the span and event attributes whose keys
+// match one of its glob patterns before passing spans on. Patterns are
+// matched case-insensitively with path.Match, so "*password*" matches
+// "user.Password".
+type redactProcessor struct {
+	sdkTrace.SpanProcessor
+	drop []string
+	hash []string
+}
+
+func newRedactProcessor(next sdkTrace.SpanProcessor, drop, hash []string) (*redactProcessor, error) {
+	lower := func(patterns []string) ([]string, error) {
+		lowered := make([]string, len(patterns))
+		for i, p := range patterns {
+			if _, err := path.Match(p, ""); err != nil {
+				return nil, fmt.Errorf("invalid attribute key pattern %q: %w", p, err)
+			}
+			lowered[i] = strings.ToLower(p)
+		}
+		return lowered, nil
+	}
+
+	dropPatterns, err := lower(drop)
+	if err != nil {
+		return nil, err
+	}
+	hashPatterns, err := lower(hash)
+	if err != nil {
+		return nil, err
+	}
+
+	return &redactProcessor{
+		SpanProcessor: next,
+		drop:          dropPatterns,
+		hash:          hashPatterns,
+	}, nil
+}
+
+func (p *redactProcessor) OnEnd(s sdkTrace.ReadOnlySpan) {
+	attrs, attrsChanged := p.redact(s.Attributes())
+
+	events := s.Events()
+	var eventsChanged bool
+	for i, e := range events {
+		redacted, changed := p.redact(e.Attributes)
+		if !changed {
+			continue
+		}
+		if !eventsChanged {
+			events = append([]sdkTrace.Event(nil), events...)
+			eventsChanged = true
+		}
+		events[i].Attributes = redacted
+	}
+
+	if attrsChanged || eventsChanged {
+		s = redactedSpan{ReadOnlySpan: s, attrs: attrs, events: events}
+	}
+	p.SpanProcessor.OnEnd(s)
+}
+
+// redact returns attrs with matching attributes dropped or hashed, and
+// whether anything changed. attrs itself is never modified.
+func (p *redactProcessor) redact(attrs []attribute.KeyValue) ([]attribute.KeyValue, bool) {
+	var redacted []attribute.KeyValue
+	for i, kv := range attrs {
+		key := strings.ToLower(string(kv.Key))
+
+		drop := matchAny(p.drop, key)
+		hash := !drop && matchAny(p.hash, key)
+		if !drop && !hash {
+			if redacted != nil {
+				redacted = append(redacted, kv)
+			}
+			continue
+		}
+
+		if redacted == nil {
+			redacted = make([]attribute.KeyValue, i, len(attrs))
+			copy(redacted, attrs[:i])
+		}
+		if hash {
+			sum := sha256.Sum256([]byte(kv.Value.Emit()))
+			redacted = append(redacted, kv.Key.String(hex.EncodeToString(sum[:])))
+		}
+	}
+
+	if redacted == nil {
+		return attrs, false
+	}
+
+	return redacted, true
+}
+
+func matchAny(patterns []string, key string) bool {
+	for _, pattern := range patterns {
+		if ok, _ := path.Match(pattern, key); ok {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,91 @@
+package tracer
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+
+	"go.opentelemetry.io/otel"
+	sdkTrace "go.opentelemetry.io/otel/sdk/trace"
+)
+
+var _ sdkTrace.SpanExporter = (*swapExporter)(nil)
+
+// swapExporter delegates to an exporter that Reload can replace while the
+// tracer provider and its batch span processor keep running.
+type swapExporter struct {
+	mu      sync.RWMutex
+	current sdkTrace.SpanExporter
+}
+
+func (e *swapExporter) ExportSpans(ctx context.Context, spans []sdkTrace.ReadOnlySpan) error {
+	e.mu.RLock()
+	defer e.mu.RUnlock()
+
+	return e.current.ExportSpans(ctx, spans)
+}
+
+func (e *swapExporter) Shutdown(ctx context.Context) error {
+	e.mu.RLock()
+	defer e.mu.RUnlock()
+
+	return e.current.Shutdown(ctx)
+}
+
+// swap installs next once the export in flight, if any, has finished and
+// returns the exporter it replaced.
+func (e *swapExporter) swap(next sdkTrace.SpanExporter) sdkTrace.SpanExporter {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	prev := e.current
+	e.current = next
+
+	return prev
+}
+
+// Reload applies the exporter and sampler settings of cfg to a running
+// tracer: a new OTLP exporter is built from the endpoint, failover,
+// protocol, credentials, headers, TLS and retry fields and replaces the
+// current one, which is shut down with ctx, and the sampler is replaced as by
+// DynamicSampler.Set. Spans keep flowing through the same provider, so
+// tokens can be rotated and collectors migrated without a restart. cfg is
+// validated as by InitTracer, but its other fields are ignored. On error
+// the tracer is left unchanged; an error shutting the previous exporter
+// down goes to otel.Handle.
+func (t *otelTracer) Reload(ctx context.Context, cfg *Config) error {
+	if t.swap == nil {
+		return errors.New("tracer was not created by InitTracer and cannot be reloaded")
+	}
+	if err := cfg.Validate(); err != nil {
+		return err
+	}
+
+	collector, err := parseEndpoint(cfg.ExporterURL)
+	if err != nil {
+		return err
+	}
+
+	sampler, err := newSampler(cfg)
+	if err != nil {
+		return err
+	}
+
+	exporter, err := newExporter(ctx, cfg)
+	if err != nil {
+		return err
+	}
+
+	prev := t.swap.swap(exporter)
+	t.sampler.Set(sampler)
+	t.collector.Store(&collector)
+
+	// The new exporter is already in use, so a failure to shut the old one
+	// down is reported without failing the reload.
+	if err := prev.Shutdown(ctx); err != nil {
+		otel.Handle(fmt.Errorf("failed to shutdown previous exporter: %w", err))
+	}
+
+	return nil
+}
@@ -0,0 +1,80 @@
+package tracer
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/sdk/resource"
+	semconv "go.opentelemetry.io/otel/semconv/v1.9.0"
+)
+
+func newResource(ctx context.Context, cfg *Config, serviceName string) (*resource.Resource, error) {
+	serviceVersion := cfg.ServiceVersion
+	if serviceVersion == "" {
+		serviceVersion = buildInfoVersion()
+	}
+
+	options := []resource.Option{
+		resource.WithAttributes(
+			semconv.ServiceNameKey.String(serviceName),
+			semconv.ServiceVersionKey.String(serviceVersion),
+			semconv.DeploymentEnvironmentKey.String(cfg.DeploymentEnvironment),
+			semconv.TelemetrySDKLanguageKey.String("go"),
+		),
+	}
+	detectors, err := resourceDetectorOptions(cfg.ResourceDetectors)
+	if err != nil {
+		return nil, err
+	}
+	options = append(options, detectors...)
+	if cfg.EnableK8sAttributesFromEnv {
+		options = append(options, resource.WithAttributes(k8sEnvAttributes()...))
+	}
+	extra := make([]attribute.KeyValue, 0, len(cfg.ResourceAttributes))
+	for key, value := range cfg.ResourceAttributes {
+		extra = append(extra, attribute.String(key, value))
+	}
+	options = append(options,
+		resource.WithAttributes(extra...),
+		resource.WithAttributes(cfg.Attributes...),
+	)
+
+	res, err := resource.New(ctx, options...)
+	if errors.Is(err, resource.ErrPartialResource) {
+		// Keep what the other detectors found.
+		otel.Handle(err)
+		err = nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to create otlp resource: %w", err)
+	}
+
+	return res, nil
+}
+
+// k8sEnvAttributes maps the environment variables conventionally populated
+// from the Kubernetes downward API to resource attributes. Unset variables
+// are skipped.
+func k8sEnvAttributes() []attribute.KeyValue {
+	vars := []struct {
+		env string
+		key attribute.Key
+	}{
+		{"POD_NAME", semconv.K8SPodNameKey},
+		{"POD_NAMESPACE", semconv.K8SNamespaceNameKey},
+		{"NODE_NAME", semconv.K8SNodeNameKey},
+	}
+
+	var attrs []attribute.KeyValue
+	for _, v := range vars {
+		if value := os.Getenv(v.env); value != "" {
+			attrs = append(attrs, v.key.String(value))
+		}
+	}
+
+	return attrs
+}
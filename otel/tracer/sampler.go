@@ -0,0 +1,182 @@
+package tracer
+
+import (
+	"encoding/binary"
+	"fmt"
+	"net/http"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	sdkTrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// ParseSampler builds a sampler from a spec in the OTEL_TRACES_SAMPLER
+// vocabulary with an optional ratio argument after a colon, e.g.
+// "always_on", "traceidratio:0.05" or "parentbased_traceidratio:0.1".
+func ParseSampler(spec string) (sdkTrace.Sampler, error) {
+	name, arg, _ := strings.Cut(strings.TrimSpace(spec), ":")
+
+	sampler, err := samplerFromEnv(name, arg)
+	if err != nil {
+		return nil, fmt.Errorf("invalid sampler spec %q: %w", spec, err)
+	}
+
+	return sampler, nil
+}
+
+const (
+	adaptiveWindow = time.Second
+	adaptiveAlpha  = 0.3
+)
+
+var _ sdkTrace.Sampler = (*adaptiveSampler)(nil)
+
+// adaptiveSampler keeps the sampled span rate near a target by measuring
+// the incoming rate as an exponential moving average over one second
+// windows and sampling with probability target/rate.
+type adaptiveSampler struct {
+	target float64
+
+	mu          sync.Mutex
+	windowStart time.Time
+	seen        int
+	rate        float64
+	probability float64
+}
+
+func newAdaptiveSampler(target float64) *adaptiveSampler {
+	return &adaptiveSampler{
+		target:      target,
+		windowStart: time.Now(),
+		probability: 1,
+	}
+}
+
+func (s *adaptiveSampler) ShouldSample(p sdkTrace.SamplingParameters) sdkTrace.SamplingResult {
+	probability := s.observe(time.Now())
+
+	decision := sdkTrace.Drop
+	// Same derivation as sdkTrace.TraceIDRatioBased so the decision is
+	// consistent for a given trace ID.
+	bound := uint64(probability * (1 << 63))
+	if binary.BigEndian.Uint64(p.TraceID[8:16])>>1 < bound {
+		decision = sdkTrace.RecordAndSample
+	}
+
+	return sdkTrace.SamplingResult{
+		Decision:   decision,
+		Tracestate: trace.SpanContextFromContext(p.ParentContext).TraceState(),
+	}
+}
+
+func (s *adaptiveSampler) observe(now time.Time) float64 {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.seen++
+	if elapsed := now.Sub(s.windowStart); elapsed >= adaptiveWindow {
+		observed := float64(s.seen) / elapsed.Seconds()
+		if s.rate == 0 {
+			s.rate = observed
+		} else {
+			s.rate = adaptiveAlpha*observed + (1-adaptiveAlpha)*s.rate
+		}
+
+		s.probability = 1
+		if s.rate > s.target {
+			s.probability = s.target / s.rate
+		}
+
+		s.windowStart = now
+		s.seen = 0
+	}
+
+	return s.probability
+}
+
+func (s *adaptiveSampler) Description() string {
+	return fmt.Sprintf("AdaptiveRate{%g}", s.target)
+}
+
+var (
+	_ sdkTrace.Sampler = (*DynamicSampler)(nil)
+	_ http.Handler     = (*DynamicSampler)(nil)
+)
+
+// DynamicSampler delegates to a sampler that can be swapped at runtime, so
+// sampling can be raised during an incident without a restart. The tracer
+// of InitTracer samples through one, see otelTracer.Sampler.
+//
+// It also serves as an admin endpoint: GET responds with the description of
+// the current sampler and POST replaces it with the sampler of the spec
+// form value, e.g. "spec=parentbased_traceidratio:0.5".
+type DynamicSampler struct {
+	current atomic.Pointer[sdkTrace.Sampler]
+}
+
+// NewDynamicSampler returns a DynamicSampler starting with initial.
+func NewDynamicSampler(initial sdkTrace.Sampler) *DynamicSampler {
+	s := new(DynamicSampler)
+	s.Set(initial)
+
+	return s
+}
+
+// Set replaces the sampler. A nil sampler samples everything, the default
+// of a Config without sampler settings.
+func (s *DynamicSampler) Set(sampler sdkTrace.Sampler) {
+	if sampler == nil {
+		sampler = sdkTrace.AlwaysSample()
+	}
+	s.current.Store(&sampler)
+}
+
+// SetRatio samples root spans with probability ratio while child spans
+// follow their parent's decision.
+func (s *DynamicSampler) SetRatio(ratio float64) error {
+	if ratio < 0 || ratio > 1 {
+		return fmt.Errorf("invalid sampler ratio %g", ratio)
+	}
+	s.Set(sdkTrace.ParentBased(sdkTrace.TraceIDRatioBased(ratio)))
+
+	return nil
+}
+
+// SetSpec replaces the sampler with the one parsed by ParseSampler.
+func (s *DynamicSampler) SetSpec(spec string) error {
+	sampler, err := ParseSampler(spec)
+	if err != nil {
+		return err
+	}
+	s.Set(sampler)
+
+	return nil
+}
+
+func (s *DynamicSampler) ShouldSample(p sdkTrace.SamplingParameters) sdkTrace.SamplingResult {
+	return (*s.current.Load()).ShouldSample(p)
+}
+
+func (s *DynamicSampler) Description() string {
+	return (*s.current.Load()).Description()
+}
+
+func (s *DynamicSampler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	switch r.Method {
+	case http.MethodGet:
+	case http.MethodPost:
+		if err := s.SetSpec(r.FormValue("spec")); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+	default:
+		w.Header().Set("Allow", "GET, POST")
+		http.Error(w, http.StatusText(http.StatusMethodNotAllowed), http.StatusMethodNotAllowed)
+		return
+	}
+
+	fmt.Fprintln(w, s.Description())
+}
@@ -0,0 +1,74 @@
+// Package samplers provides head samplers for use with tracer.Config.Sampler.
+package samplers
+
+import (
+	"fmt"
+	"sync"
+	"time"
+
+	sdkTrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// RateLimiting returns a parent-based sampler that samples at most n root
+// spans per second. Unlike ratio sampling it caps the volume during traffic
+// bursts; child spans follow their parent's decision. A non-positive n
+// drops every root span.
+func RateLimiting(n int) sdkTrace.Sampler {
+	return sdkTrace.ParentBased(newRateLimiter(n, time.Now()))
+}
+
+var _ sdkTrace.Sampler = (*rateLimiter)(nil)
+
+// rateLimiter is a token bucket holding up to limit tokens that refills at
+// limit tokens per second. Each sampled span takes one token.
+type rateLimiter struct {
+	limit float64
+
+	mu       sync.Mutex
+	tokens   float64
+	lastFill time.Time
+}
+
+func newRateLimiter(n int, now time.Time) *rateLimiter {
+	limit := float64(max(n, 0))
+
+	return &rateLimiter{
+		limit:    limit,
+		tokens:   limit,
+		lastFill: now,
+	}
+}
+
+func (s *rateLimiter) ShouldSample(p sdkTrace.SamplingParameters) sdkTrace.SamplingResult {
+	decision := sdkTrace.Drop
+	if s.take(time.Now()) {
+		decision = sdkTrace.RecordAndSample
+	}
+
+	return sdkTrace.SamplingResult{
+		Decision:   decision,
+		Tracestate: trace.SpanContextFromContext(p.ParentContext).TraceState(),
+	}
+}
+
+func (s *rateLimiter) take(now time.Time) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if elapsed := now.Sub(s.lastFill); elapsed > 0 {
+		s.tokens = min(s.limit, s.tokens+elapsed.Seconds()*s.limit)
+		s.lastFill = now
+	}
+
+	if s.tokens < 1 {
+		return false
+	}
+	s.tokens--
+
+	return true
+}
+
+func (s *rateLimiter) Description() string {
+	return fmt.Sprintf("RateLimiting{%g}", s.limit)
+}
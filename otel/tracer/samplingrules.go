@@ -0,0 +1,67 @@
+package tracer
+
+import (
+	"fmt"
+	"strings"
+
+	sdkTrace "go.opentelemetry.io/otel/sdk/trace"
+)
+
+// SamplingRule samples root spans whose name or HTTP request matches Match,
+// a pattern as in Config.DropSpanNames, with probability Ratio.
+type SamplingRule struct {
+	Match string
+	Ratio float64
+}
+
+var _ sdkTrace.Sampler = (*ruleSampler)(nil)
+
+// ruleSampler samples root spans with the ratio of the first matching rule
+// and defers to fallback for the others.
+type ruleSampler struct {
+	rules    []compiledRule
+	fallback sdkTrace.Sampler
+}
+
+type compiledRule struct {
+	patterns spanNamePatterns
+	sampler  sdkTrace.Sampler
+}
+
+// newRuleSampler compiles rules in front of fallback. Child spans follow
+// their parent, so a trace is kept or dropped as a whole.
+func newRuleSampler(rules []SamplingRule, fallback sdkTrace.Sampler) (sdkTrace.Sampler, error) {
+	compiled := make([]compiledRule, 0, len(rules))
+	for _, rule := range rules {
+		patterns, err := newSpanNamePatterns([]string{rule.Match})
+		if err != nil {
+			return nil, err
+		}
+		if rule.Ratio < 0 || rule.Ratio > 1 {
+			return nil, fmt.Errorf("invalid sampling rule ratio %g for %q", rule.Ratio, rule.Match)
+		}
+		compiled = append(compiled, compiledRule{patterns: patterns, sampler: sdkTrace.TraceIDRatioBased(rule.Ratio)})
+	}
+
+	return sdkTrace.ParentBased(ruleSampler{rules: compiled, fallback: fallback}), nil
+}
+
+func (s ruleSampler) ShouldSample(p sdkTrace.SamplingParameters) sdkTrace.SamplingResult {
+	request := requestName(p.Attributes)
+	for _, rule := range s.rules {
+		if rule.patterns.match(p.Name) || rule.patterns.match(request) {
+			return rule.sampler.ShouldSample(p)
+		}
+	}
+
+	return s.fallback.ShouldSample(p)
+}
+
+func (s ruleSampler) Description() string {
+	rules := make([]string, len(s.rules))
+	for i, rule := range s.rules {
+		rules[i] = rule.patterns[0] + "=" + rule.sampler.Description()
+	}
+
+	return fmt.Sprintf("SamplingRules{%s}+%s", strings.Join(rules, ","), s.fallback.Description())
+}
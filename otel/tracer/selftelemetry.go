@@ -0,0 +1,117 @@
+package tracer
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/metric"
+	sdkTrace "go.opentelemetry.io/otel/sdk/trace"
+)
+
+// pipelineMetrics are the instruments of Config.SelfTelemetry.
+type pipelineMetrics struct {
+	started        metric.Int64Counter
+	exported       metric.Int64Counter
+	exportDuration metric.Float64Histogram
+}
+
+func newPipelineMetrics(mp metric.MeterProvider, queue *queueDepth) (*pipelineMetrics, error) {
+	meter := mp.Meter(instrumentationName)
+
+	started, err1 := meter.Int64Counter("otel.tracer.span.started",
+		metric.WithDescription("Spans started, by sampling decision."),
+		metric.WithUnit("{span}"),
+	)
+	exported, err2 := meter.Int64Counter("otel.tracer.span.exported",
+		metric.WithDescription("Spans handed to the OTLP exporter, by export result."),
+		metric.WithUnit("{span}"),
+	)
+	exportDuration, err3 := meter.Float64Histogram("otel.tracer.export.duration",
+		metric.WithDescription("Duration of OTLP exports, by export result."),
+		metric.WithUnit("s"),
+	)
+	dropped, err4 := meter.Int64ObservableCounter("otel.tracer.span.dropped",
+		metric.WithDescription("Sampled spans dropped because the export queue was full."),
+		metric.WithUnit("{span}"),
+	)
+	depth, err5 := meter.Int64ObservableGauge("otel.tracer.queue.size",
+		metric.WithDescription("Spans waiting in the export queue."),
+		metric.WithUnit("{span}"),
+	)
+	if err := errors.Join(err1, err2, err3, err4, err5); err != nil {
+		return nil, fmt.Errorf("failed to create self-telemetry instruments: %w", err)
+	}
+
+	_, err := meter.RegisterCallback(func(_ context.Context, o metric.Observer) error {
+		o.ObserveInt64(dropped, queue.dropped.Load())
+		o.ObserveInt64(depth, int64(queue.depth()))
+		return nil
+	}, dropped, depth)
+	if err != nil {
+		return nil, fmt.Errorf("failed to register self-telemetry callback: %w", err)
+	}
+
+	return &pipelineMetrics{
+		started:        started,
+		exported:       exported,
+		exportDuration: exportDuration,
+	}, nil
+}
+
+var (
+	sampledAttrs    = metric.WithAttributeSet(attribute.NewSet(attribute.Bool("sampled", true)))
+	notSampledAttrs = metric.WithAttributeSet(attribute.NewSet(attribute.Bool("sampled", false)))
+	successAttrs    = metric.WithAttributeSet(attribute.NewSet(attribute.String("result", "success")))
+	failureAttrs    = metric.WithAttributeSet(attribute.NewSet(attribute.String("result", "failure")))
+)
+
+var _ sdkTrace.Sampler = (*pipelineMetricsSampler)(nil)
+
+// pipelineMetricsSampler counts started spans by the decision of its
+// sampler. It wraps the final sampler rather than observing OnStart, which
+// the SDK only calls for recorded spans, so spans dropped by head sampling
+// are counted too.
+type pipelineMetricsSampler struct {
+	sdkTrace.Sampler
+	metrics *pipelineMetrics
+}
+
+func (s *pipelineMetricsSampler) ShouldSample(p sdkTrace.SamplingParameters) sdkTrace.SamplingResult {
+	result := s.Sampler.ShouldSample(p)
+
+	attrs := notSampledAttrs
+	if result.Decision == sdkTrace.RecordAndSample {
+		attrs = sampledAttrs
+	}
+	s.metrics.started.Add(context.WithoutCancel(p.ParentContext), 1, attrs)
+
+	return result
+}
+
+var _ sdkTrace.SpanExporter = (*pipelineMetricsExporter)(nil)
+
+// pipelineMetricsExporter counts exported spans and times exports.
+type pipelineMetricsExporter struct {
+	sdkTrace.SpanExporter
+	metrics *pipelineMetrics
+}
+
+func (e *pipelineMetricsExporter) ExportSpans(ctx context.Context, spans []sdkTrace.ReadOnlySpan) error {
+	start := time.Now()
+	err := e.SpanExporter.ExportSpans(ctx, spans)
+
+	attrs := successAttrs
+	if err != nil {
+		attrs = failureAttrs
+	}
+	// The export context may be canceled already, which must not drop the
+	// measurements.
+	ctx = context.WithoutCancel(ctx)
+	e.metrics.exported.Add(ctx, int64(len(spans)), attrs)
+	e.metrics.exportDuration.Record(ctx, time.Since(start).Seconds(), attrs)
+
+	return err
+}
@@ -0,0 +1,91 @@
+package tracer
+
+import (
+	"context"
+	"errors"
+	"os"
+	"os/signal"
+	"sync"
+	"syscall"
+	"time"
+
+	"go.opentelemetry.io/otel"
+)
+
+const defaultShutdownTimeout = 5 * time.Second
+
+// Shutdowner is implemented by the tracer, meter and logger of this module.
+// Those that also have a ForceFlush method are flushed before shutdown.
+type Shutdowner interface {
+	Shutdown(ctx context.Context) error
+}
+
+type flusher interface {
+	ForceFlush(ctx context.Context) error
+}
+
+// HandleShutdownSignals flushes and shuts down the tracer when one of sigs
+// (os.Interrupt and SIGTERM by default) is received. Errors are reported to
+// the global OpenTelemetry error handler. Once notified, the process no
+// longer terminates on those signals by default, so the application is
+// expected to exit on its own. The returned func uninstalls the handler.
+func (t *otelTracer) HandleShutdownSignals(ctx context.Context, sigs ...os.Signal) func() {
+	if len(sigs) == 0 {
+		sigs = []os.Signal{os.Interrupt, syscall.SIGTERM}
+	}
+
+	ch := make(chan os.Signal, 1)
+	signal.Notify(ch, sigs...)
+
+	done := make(chan struct{})
+	go func() {
+		defer signal.Stop(ch)
+
+		select {
+		case <-ch:
+		case <-done:
+			return
+		}
+
+		if err := t.ForceFlush(ctx); err != nil {
+			otel.Handle(err)
+		}
+		if err := t.Shutdown(ctx); err != nil {
+			otel.Handle(err)
+		}
+	}()
+
+	var once sync.Once
+	return func() {
+		once.Do(func() {
+			close(done)
+		})
+	}
+}
+
+// RunUntilShutdown runs fn with a context that is cancelled when os.Interrupt
+// or SIGTERM is received or ctx is done. Once fn returns, the providers are
+// flushed and shut down in the order given, within timeout overall (5s if
+// zero), so short-lived jobs keep their final spans. It returns the errors
+// of fn and of the shutdown joined.
+func RunUntilShutdown(ctx context.Context, timeout time.Duration, fn func(ctx context.Context) error, providers ...Shutdowner) error {
+	if timeout <= 0 {
+		timeout = defaultShutdownTimeout
+	}
+
+	runCtx, stop := signal.NotifyContext(ctx, os.Interrupt, syscall.SIGTERM)
+	errs := []error{fn(runCtx)}
+	stop()
+
+	shutdownCtx, cancel := context.WithTimeout(context.WithoutCancel(ctx), timeout)
+	defer cancel()
+
+	for _, p := range providers {
+		if f, ok := p.(flusher); ok {
+			errs = append(errs, f.ForceFlush(shutdownCtx))
+		}
+		errs = append(errs, p.Shutdown(shutdownCtx))
+	}
+
+	return errors.Join(errs...)
+}
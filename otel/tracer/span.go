@@ -0,0 +1,163 @@
+package tracer
+
+import (
+	"context"
+	"fmt"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	sdkTrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/trace"
+)
+
+const instrumentationName = "github.com/0x5w4/go-otel/otel/tracer"
+
+// dropKey marks a span that must not be exported.
+const dropKey = attribute.Key("otel.drop")
+
+// panicKey marks the exception event of a recovered panic.
+const panicKey = attribute.Key("exception.panic")
+
+type tracerContextKey struct{}
+
+// ContextWithTracer returns a copy of ctx carrying t. StartSpan prefers that
+// tracer over the global one for every span started from the returned
+// context or its descendants.
+func ContextWithTracer(ctx context.Context, t trace.Tracer) context.Context {
+	return context.WithValue(ctx, tracerContextKey{}, t)
+}
+
+// TracerFromContext returns the tracer stored by ContextWithTracer, or the
+// global tracer when none is stored.
+func TracerFromContext(ctx context.Context) trace.Tracer {
+	if t, ok := ctx.Value(tracerContextKey{}).(trace.Tracer); ok && t != nil {
+		return t
+	}
+
+	return otel.Tracer(instrumentationName)
+}
+
+// StartSpan starts a span with the tracer selected by TracerFromContext.
+// With Config.ProfilingLabels the span is also attached to the CPU
+// profiles of the goroutine until it ends.
+func StartSpan(ctx context.Context, name string, opts ...trace.SpanStartOption) (context.Context, trace.Span) {
+	spanCtx, span := TracerFromContext(ctx).Start(ctx, name, opts...)
+	return withProfilingLabels(ctx, spanCtx, name, span)
+}
+
+// Span wraps a trace.Span with shortcuts for routine error handling.
+type Span struct {
+	trace.Span
+}
+
+// Start starts a span with the tracer selected by TracerFromContext and
+// sets attrs on it.
+func Start(ctx context.Context, name string, attrs ...attribute.KeyValue) (context.Context, Span) {
+	ctx, span := StartSpan(ctx, name, trace.WithAttributes(attrs...))
+	return ctx, Span{Span: span}
+}
+
+// Fail records err on the span and sets its status to Error. A nil err is
+// ignored.
+func (s Span) Fail(err error) {
+	s.fail(err, false)
+}
+
+// FailWithStack is Fail with the stack trace of the caller attached to the
+// recorded exception.
+func (s Span) FailWithStack(err error) {
+	s.fail(err, true)
+}
+
+func (s Span) fail(err error, stack bool) {
+	if err == nil {
+		return
+	}
+	s.RecordError(err, trace.WithStackTrace(stack))
+	s.SetStatus(codes.Error, err.Error())
+}
+
+// Finish ends the span, failing it first when err is not nil.
+func (s Span) Finish(err error) {
+	s.Fail(err)
+	s.End()
+}
+
+// Error records err with a stack trace and attrs on the span in ctx, sets
+// the span status to Error and returns err, so error returns stay one line:
+//
+//	if err != nil {
+//		return tracer.Error(ctx, err, attribute.String("order.id", id))
+//	}
+//
+// It returns nil for a nil err.
+func Error(ctx context.Context, err error, attrs ...attribute.KeyValue) error {
+	if err == nil {
+		return nil
+	}
+
+	span := trace.SpanFromContext(ctx)
+	span.RecordError(err, trace.WithStackTrace(true), trace.WithAttributes(attrs...))
+	span.SetStatus(codes.Error, err.Error())
+
+	return err
+}
+
+// Wrap is Error for an err wrapped with msg as in fmt.Errorf("msg: %w").
+// The wrapped error is the one recorded.
+func Wrap(ctx context.Context, err error, msg string, attrs ...attribute.KeyValue) error {
+	if err == nil {
+		return nil
+	}
+
+	return Error(ctx, fmt.Errorf("%s: %w", msg, err), attrs...)
+}
+
+// Trace starts a span and returns a func that ends it. The func is meant to
+// be deferred with a pointer to the caller's named error result, so the
+// error is read when the function returns rather than when the defer runs:
+//
+//	func load(ctx context.Context) (err error) {
+//		ctx, finish := tracer.Trace(ctx, "load")
+//		defer finish(&err)
+//		...
+//	}
+func Trace(ctx context.Context, name string, opts ...trace.SpanStartOption) (context.Context, func(err *error)) {
+	ctx, span := StartSpan(ctx, name, opts...)
+
+	return ctx, func(err *error) {
+		if err != nil && *err != nil {
+			span.RecordError(*err)
+			span.SetStatus(codes.Error, (*err).Error())
+		}
+		span.End()
+	}
+}
+
+// SetSpanName renames the span in ctx, for spans whose best name is only
+// known once the work is done, e.g. after routing. It is a no-op for spans
+// that are not recording.
+func SetSpanName(ctx context.Context, name string) {
+	span := trace.SpanFromContext(ctx)
+	if !span.IsRecording() {
+		return
+	}
+	span.SetName(name)
+}
+
+// DropSpan marks the span in ctx so that it is discarded when it ends
+// instead of being exported.
+func DropSpan(ctx context.Context) {
+	trace.SpanFromContext(ctx).SetAttributes(dropKey.Bool(true))
+}
+
+func isDropped(s sdkTrace.ReadOnlySpan) bool {
+	for _, kv := range s.Attributes() {
+		if kv.Key == dropKey {
+			return kv.Value.AsBool()
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,85 @@
+package tracer
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/metric"
+	sdkTrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/trace"
+)
+
+var _ sdkTrace.SpanProcessor = (*spanMetricsProcessor)(nil)
+
+// spanMetricsProcessor derives request, error and duration (RED) metrics
+// from every ended span, sampled or not, with the dimensions and names of
+// the Collector's spanmetrics connector: span.name, span.kind and
+// status.code, plus the span attributes in keys. It sits behind the
+// normalize, redact and OK status processors, so the metrics use the names,
+// attribute values and status that are exported.
+type spanMetricsProcessor struct {
+	sdkTrace.SpanProcessor
+	calls    metric.Int64Counter
+	errors   metric.Int64Counter
+	duration metric.Float64Histogram
+	keys     []attribute.Key
+}
+
+func newSpanMetricsProcessor(next sdkTrace.SpanProcessor, mp metric.MeterProvider, keys []string) (*spanMetricsProcessor, error) {
+	meter := mp.Meter(instrumentationName)
+
+	calls, err1 := meter.Int64Counter("traces.span.metrics.calls",
+		metric.WithDescription("Spans ended, by name, kind and status."),
+		metric.WithUnit("{call}"),
+	)
+	errs, err2 := meter.Int64Counter("traces.span.metrics.errors",
+		metric.WithDescription("Spans ended with an error status, by name and kind."),
+		metric.WithUnit("{call}"),
+	)
+	duration, err3 := meter.Float64Histogram("traces.span.metrics.duration",
+		metric.WithDescription("Duration of spans, by name, kind and status."),
+		metric.WithUnit("s"),
+	)
+	if err := errors.Join(err1, err2, err3); err != nil {
+		return nil, fmt.Errorf("failed to create span metrics instruments: %w", err)
+	}
+
+	p := &spanMetricsProcessor{SpanProcessor: next, calls: calls, errors: errs, duration: duration}
+	for _, key := range keys {
+		p.keys = append(p.keys, attribute.Key(key))
+	}
+
+	return p, nil
+}
+
+func (p *spanMetricsProcessor) OnEnd(s sdkTrace.ReadOnlySpan) {
+	attrs := make([]attribute.KeyValue, 0, 3+len(p.keys))
+	attrs = append(attrs,
+		attribute.String("span.name", s.Name()),
+		attribute.String("span.kind", "SPAN_KIND_"+strings.ToUpper(s.SpanKind().String())),
+		attribute.String("status.code", "STATUS_CODE_"+strings.ToUpper(s.Status().Code.String())),
+	)
+	for _, key := range p.keys {
+		for _, kv := range s.Attributes() {
+			if kv.Key == key {
+				attrs = append(attrs, kv)
+				break
+			}
+		}
+	}
+	opt := metric.WithAttributeSet(attribute.NewSet(attrs...))
+
+	// The span context lets sampled spans become exemplars of the metrics.
+	ctx := trace.ContextWithSpanContext(context.Background(), s.SpanContext())
+	p.calls.Add(ctx, 1, opt)
+	if s.Status().Code == codes.Error {
+		p.errors.Add(ctx, 1, opt)
+	}
+	p.duration.Record(ctx, s.EndTime().Sub(s.StartTime()).Seconds(), opt)
+
+	p.SpanProcessor.OnEnd(s)
+}
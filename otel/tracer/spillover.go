@@ -0,0 +1,359 @@
+package tracer
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"go.opentelemetry.io/otel"
+	sdkTrace "go.opentelemetry.io/otel/sdk/trace"
+)
+
+const (
+	defaultSpilloverMaxBytes      = 64 << 20
+	defaultSpilloverRetryInterval = 15 * time.Second
+
+	spillFileExt = ".json"
+)
+
+var _ sdkTrace.SpanExporter = (*spilloverExporter)(nil)
+
+// spilloverExporter writes the batches its exporter fails to export, and
+// the spans a full queue hands to overflow, to files in dir as OTLP/JSON,
+// one batch per file. A background loop replays the files, oldest first,
+// every retry interval and right after an export succeeds, stopping at the
+// first failure. The files outlive the process and are replayed by the next
+// tracer using dir. Once they would exceed maxBytes the oldest are removed.
+//
+// Replayed batches go to replayTo, the exporter below the export hooks, so
+// OnExport and LastExportStatus only report the batches of the processor.
+// exportMu keeps replay and live exports from calling the exporters
+// concurrently, which the SpanExporter contract does not allow.
+type spilloverExporter struct {
+	sdkTrace.SpanExporter
+	replayTo      sdkTrace.SpanExporter
+	dir           string
+	maxBytes      int64
+	retry         time.Duration
+	exportTimeout time.Duration
+	batchSize     int
+	logger        *slog.Logger
+
+	exportMu sync.Mutex
+
+	mu      sync.Mutex
+	files   []spillFile
+	size    int64
+	seq     int
+	pending []sdkTrace.ReadOnlySpan
+
+	startOnce sync.Once
+	wake      chan struct{}
+	stop      chan struct{}
+	stopOnce  sync.Once
+	done      chan struct{}
+}
+
+type spillFile struct {
+	name string
+	size int64
+}
+
+func newSpilloverExporter(next, replayTo sdkTrace.SpanExporter, cfg *Config, logger *slog.Logger) (*spilloverExporter, error) {
+	e := &spilloverExporter{
+		SpanExporter:  next,
+		replayTo:      replayTo,
+		dir:           cfg.SpilloverDir,
+		maxBytes:      cfg.SpilloverMaxBytes,
+		retry:         cfg.SpilloverRetryInterval,
+		exportTimeout: cfg.ExportTimeout,
+		batchSize:     cfg.MaxExportBatchSize,
+		logger:        logger,
+		wake:          make(chan struct{}, 1),
+		stop:          make(chan struct{}),
+		done:          make(chan struct{}),
+	}
+	if e.maxBytes <= 0 {
+		e.maxBytes = defaultSpilloverMaxBytes
+	}
+	if e.retry <= 0 {
+		e.retry = defaultSpilloverRetryInterval
+	}
+	if e.exportTimeout <= 0 {
+		e.exportTimeout = sdkTrace.DefaultExportTimeout * time.Millisecond
+	}
+	if e.batchSize <= 0 {
+		e.batchSize = sdkTrace.DefaultMaxExportBatchSize
+	}
+
+	if err := os.MkdirAll(e.dir, 0o755); err != nil {
+		return nil, fmt.Errorf("failed to create spillover directory: %w", err)
+	}
+	entries, err := os.ReadDir(e.dir)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read spillover directory: %w", err)
+	}
+	// File names start with the time they were written, so the sorted
+	// entries of ReadDir are in replay order.
+	for _, entry := range entries {
+		if strings.HasSuffix(entry.Name(), spillFileExt+".tmp") {
+			os.Remove(filepath.Join(e.dir, entry.Name()))
+			continue
+		}
+		if entry.IsDir() || !strings.HasSuffix(entry.Name(), spillFileExt) {
+			continue
+		}
+		info, err := entry.Info()
+		if err != nil {
+			continue
+		}
+		e.files = append(e.files, spillFile{name: entry.Name(), size: info.Size()})
+		e.size += info.Size()
+	}
+
+	return e, nil
+}
+
+func (e *spilloverExporter) ExportSpans(ctx context.Context, spans []sdkTrace.ReadOnlySpan) error {
+	e.startOnce.Do(e.start)
+
+	e.exportMu.Lock()
+	err := e.SpanExporter.ExportSpans(ctx, spans)
+	e.exportMu.Unlock()
+	if err == nil {
+		e.signal()
+		return nil
+	}
+
+	if spillErr := e.spill(spans); spillErr != nil {
+		return errors.Join(err, spillErr)
+	}
+
+	return nil
+}
+
+// overflow buffers a span the queue had no room for, spilling the buffer
+// once it holds a full batch.
+func (e *spilloverExporter) overflow(s sdkTrace.ReadOnlySpan) {
+	e.startOnce.Do(e.start)
+
+	e.mu.Lock()
+	e.pending = append(e.pending, s)
+	var batch []sdkTrace.ReadOnlySpan
+	if len(e.pending) >= e.batchSize {
+		batch, e.pending = e.pending, nil
+	}
+	e.mu.Unlock()
+
+	if batch != nil {
+		if err := e.spill(batch); err != nil {
+			otel.Handle(err)
+		}
+	}
+}
+
+func (e *spilloverExporter) flushPending() {
+	e.mu.Lock()
+	batch := e.pending
+	e.pending = nil
+	e.mu.Unlock()
+
+	if len(batch) > 0 {
+		if err := e.spill(batch); err != nil {
+			otel.Handle(err)
+		}
+	}
+}
+
+// spill writes spans to a new file, removing the oldest files as needed to
+// stay within maxBytes. The file is written under a temporary name first,
+// so a crash never leaves a partial batch to replay.
+func (e *spilloverExporter) spill(spans []sdkTrace.ReadOnlySpan) error {
+	data, err := json.Marshal(otlpJSONTraces(spans))
+	if err != nil {
+		return fmt.Errorf("failed to encode spilled spans: %w", err)
+	}
+	size := int64(len(data))
+	if size > e.maxBytes {
+		return fmt.Errorf("failed to spill spans: batch of %d bytes exceeds SpilloverMaxBytes", size)
+	}
+
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	was := len(e.files)
+	for e.size+size > e.maxBytes && len(e.files) > 0 {
+		oldest := e.files[0]
+		if err := os.Remove(filepath.Join(e.dir, oldest.name)); err != nil && !errors.Is(err, fs.ErrNotExist) {
+			return fmt.Errorf("failed to remove spilled spans: %w", err)
+		}
+		e.files = e.files[1:]
+		e.size -= oldest.size
+		e.logger.Warn("span spillover buffer full, dropped the oldest batch", "bytes", oldest.size)
+	}
+
+	e.seq++
+	name := fmt.Sprintf("%020d-%06d%s", time.Now().UnixNano(), e.seq%1000000, spillFileExt)
+	path := filepath.Join(e.dir, name)
+	if err := os.WriteFile(path+".tmp", data, 0o644); err != nil {
+		return fmt.Errorf("failed to spill spans: %w", err)
+	}
+	if err := os.Rename(path+".tmp", path); err != nil {
+		os.Remove(path + ".tmp")
+		return fmt.Errorf("failed to spill spans: %w", err)
+	}
+
+	e.files = append(e.files, spillFile{name: name, size: size})
+	e.size += size
+	if was == 0 {
+		e.logger.Warn("otlp export failing, buffering spans on disk", "dir", e.dir)
+	}
+
+	return nil
+}
+
+// signal wakes the replay loop if there are files to replay.
+func (e *spilloverExporter) signal() {
+	e.mu.Lock()
+	empty := len(e.files) == 0
+	e.mu.Unlock()
+
+	if empty {
+		return
+	}
+	select {
+	case e.wake <- struct{}{}:
+	default:
+	}
+}
+
+func (e *spilloverExporter) start() {
+	go func() {
+		defer close(e.done)
+
+		ticker := time.NewTicker(e.retry)
+		defer ticker.Stop()
+
+		e.replay()
+		for {
+			select {
+			case <-ticker.C:
+				e.flushPending()
+				e.replay()
+			case <-e.wake:
+				e.replay()
+			case <-e.stop:
+				return
+			}
+		}
+	}()
+}
+
+// replay exports the spilled files in order until one fails.
+func (e *spilloverExporter) replay() {
+	replayed := false
+	for {
+		select {
+		case <-e.stop:
+			return
+		default:
+		}
+
+		e.mu.Lock()
+		if len(e.files) == 0 {
+			e.mu.Unlock()
+			if replayed {
+				e.logger.Info("replayed the spans buffered on disk")
+			}
+			return
+		}
+		file := e.files[0]
+		e.mu.Unlock()
+
+		// Files that cannot be read are dropped rather than retried.
+		spans, err := e.read(file.name)
+		if err != nil {
+			otel.Handle(err)
+		} else if len(spans) > 0 {
+			err = e.export(spans)
+			if err != nil {
+				return
+			}
+		}
+
+		e.remove(file)
+		replayed = true
+	}
+}
+
+// export replays spans once no live export is running.
+func (e *spilloverExporter) export(spans []sdkTrace.ReadOnlySpan) error {
+	e.exportMu.Lock()
+	defer e.exportMu.Unlock()
+
+	ctx, cancel := context.WithTimeout(context.Background(), e.exportTimeout)
+	defer cancel()
+
+	return e.replayTo.ExportSpans(ctx, spans)
+}
+
+func (e *spilloverExporter) read(name string) ([]sdkTrace.ReadOnlySpan, error) {
+	data, err := os.ReadFile(filepath.Join(e.dir, name))
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read spilled spans: %w", err)
+	}
+
+	var traces otlpTraces
+	if err := json.Unmarshal(data, &traces); err != nil {
+		return nil, fmt.Errorf("failed to decode spilled spans: %w", err)
+	}
+	spans, err := spansFromOTLPJSON(traces)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode spilled spans: %w", err)
+	}
+
+	return spans, nil
+}
+
+// remove deletes file unless spill has already removed it to make room.
+func (e *spilloverExporter) remove(file spillFile) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	i := slices.Index(e.files, file)
+	if i < 0 {
+		return
+	}
+	if err := os.Remove(filepath.Join(e.dir, file.name)); err != nil && !errors.Is(err, fs.ErrNotExist) {
+		otel.Handle(fmt.Errorf("failed to remove spilled spans: %w", err))
+	}
+	e.files = slices.Delete(e.files, i, i+1)
+	e.size -= file.size
+}
+
+// Shutdown stops replaying and spills the overflow spans still buffered,
+// leaving the files for the next tracer, before shutting its exporter down.
+func (e *spilloverExporter) Shutdown(ctx context.Context) error {
+	e.stopOnce.Do(func() {
+		close(e.stop)
+	})
+	e.startOnce.Do(func() {
+		close(e.done)
+	})
+	<-e.done
+	e.flushPending()
+
+	return e.SpanExporter.Shutdown(ctx)
+}
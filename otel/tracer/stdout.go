@@ -0,0 +1,35 @@
+package tracer
+
+import (
+	"context"
+	"fmt"
+
+	"go.opentelemetry.io/otel/exporters/stdout/stdouttrace"
+)
+
+// InitStdoutTracer is InitTracer for local development: spans are
+// pretty-printed to stdout instead of being sent to a collector, so no
+// exporter settings are required.
+func InitStdoutTracer(ctx context.Context, opts ...Option) (Tracer, error) {
+	cfg := new(Config)
+	for _, opt := range opts {
+		opt.apply(cfg)
+	}
+
+	serviceName := resolveServiceName(cfg)
+	if serviceName == "" {
+		return nil, fmt.Errorf("service name is missing in the stdout tracer configuration")
+	}
+
+	exporter, err := stdouttrace.New(stdouttrace.WithPrettyPrint())
+	if err != nil {
+		return nil, fmt.Errorf("failed to create stdout exporter: %w", err)
+	}
+
+	t, err := newTracer(ctx, cfg, serviceName, exporter)
+	if err != nil {
+		return nil, err
+	}
+
+	return t, nil
+}
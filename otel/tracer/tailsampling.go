@@ -0,0 +1,149 @@
+package tracer
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"go.opentelemetry.io/otel/codes"
+	sdkTrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/trace"
+)
+
+var _ sdkTrace.Sampler = (*recordOnlySampler)(nil)
+
+// recordOnlySampler records the spans its sampler drops, so the tail
+// sampling and span metrics processors still see them when they end.
+type recordOnlySampler struct {
+	sdkTrace.Sampler
+}
+
+func (s recordOnlySampler) ShouldSample(p sdkTrace.SamplingParameters) sdkTrace.SamplingResult {
+	result := s.Sampler.ShouldSample(p)
+	if result.Decision == sdkTrace.Drop {
+		result.Decision = sdkTrace.RecordOnly
+	}
+
+	return result
+}
+
+// sampledSpan marks an ended span as sampled so the batcher exports it.
+type sampledSpan struct {
+	sdkTrace.ReadOnlySpan
+}
+
+func (s sampledSpan) SpanContext() trace.SpanContext {
+	sc := s.ReadOnlySpan.SpanContext()
+	return sc.WithTraceFlags(sc.TraceFlags().WithSampled(true))
+}
+
+var _ sdkTrace.SpanProcessor = (*tailSamplingProcessor)(nil)
+
+// tailSamplingProcessor buffers the spans the head sampler did not sample
+// per trace and passes the whole trace on once one of its spans ends with
+// an error status or takes longer than latency. Traces are forgotten window
+// after their first buffered span; the buffer is capped at
+// sdkTrace.DefaultMaxQueueSize spans.
+type tailSamplingProcessor struct {
+	sdkTrace.SpanProcessor
+	window  time.Duration
+	latency time.Duration
+
+	mu        sync.Mutex
+	traces    map[trace.TraceID]*tailTrace
+	buffered  int
+	lastSweep time.Time
+}
+
+type tailTrace struct {
+	first time.Time
+	keep  bool
+	spans []sdkTrace.ReadOnlySpan
+}
+
+func newTailSamplingProcessor(next sdkTrace.SpanProcessor, window, latency time.Duration) *tailSamplingProcessor {
+	return &tailSamplingProcessor{
+		SpanProcessor: next,
+		window:        window,
+		latency:       latency,
+		traces:        make(map[trace.TraceID]*tailTrace),
+		lastSweep:     time.Now(),
+	}
+}
+
+func (p *tailSamplingProcessor) OnEnd(s sdkTrace.ReadOnlySpan) {
+	if s.SpanContext().IsSampled() {
+		p.SpanProcessor.OnEnd(s)
+		return
+	}
+
+	release := p.buffer(s, time.Now())
+	for _, rs := range release {
+		p.SpanProcessor.OnEnd(sampledSpan{rs})
+	}
+}
+
+// buffer records s under its trace and returns the spans to pass on.
+func (p *tailSamplingProcessor) buffer(s sdkTrace.ReadOnlySpan, now time.Time) []sdkTrace.ReadOnlySpan {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	p.sweep(now)
+
+	traceID := s.SpanContext().TraceID()
+	t, ok := p.traces[traceID]
+	if !ok {
+		t = &tailTrace{first: now}
+		p.traces[traceID] = t
+	}
+
+	if t.keep {
+		return []sdkTrace.ReadOnlySpan{s}
+	}
+
+	if !p.interesting(s) {
+		if p.buffered < sdkTrace.DefaultMaxQueueSize {
+			t.spans = append(t.spans, s)
+			p.buffered++
+		}
+		return nil
+	}
+
+	release := append(t.spans, s)
+	p.buffered -= len(t.spans)
+	t.spans = nil
+	t.keep = true
+
+	return release
+}
+
+func (p *tailSamplingProcessor) interesting(s sdkTrace.ReadOnlySpan) bool {
+	if s.Status().Code == codes.Error {
+		return true
+	}
+
+	return p.latency > 0 && s.EndTime().Sub(s.StartTime()) > p.latency
+}
+
+func (p *tailSamplingProcessor) sweep(now time.Time) {
+	if now.Sub(p.lastSweep) < p.window {
+		return
+	}
+	p.lastSweep = now
+
+	for traceID, t := range p.traces {
+		if now.Sub(t.first) > p.window {
+			p.buffered -= len(t.spans)
+			delete(p.traces, traceID)
+		}
+	}
+}
+
+func (p *tailSamplingProcessor) Shutdown(ctx context.Context) error {
+	p.mu.Lock()
+	p.traces = make(map[trace.TraceID]*tailTrace)
+	p.buffered = 0
+	p.mu.Unlock()
+
+	return p.SpanProcessor.Shutdown(ctx)
+}
@@ -0,0 +1,128 @@
+package tracer
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"os"
+	"sync"
+	"time"
+
+	"google.golang.org/grpc/credentials"
+)
+
+// transportCredentials returns the TLS credentials for the gRPC exporter
+// connection, or nil for a plaintext connection. Configured Creds take
+// precedence over the TLS fields. Like them, they are ignored for an
+// http:// endpoint.
+func transportCredentials(cfg *Config, scheme string) (credentials.TransportCredentials, error) {
+	if cfg.Creds != nil && !cfg.Insecure && scheme != "http" {
+		return *cfg.Creds, nil
+	}
+
+	tlsConfig, err := exporterTLSConfig(cfg, scheme)
+	if err != nil || tlsConfig == nil {
+		return nil, err
+	}
+
+	return credentials.NewTLS(tlsConfig), nil
+}
+
+// exporterTLSConfig returns the TLS configuration for the collector
+// connection, or nil for plaintext. Insecure and an http:// endpoint force
+// plaintext and otherwise any of the TLS fields force TLS; only when
+// neither is set does the endpoint scheme decide, with https:// enabling TLS.
+func exporterTLSConfig(cfg *Config, scheme string) (*tls.Config, error) {
+	if cfg.Insecure || scheme == "http" {
+		return nil, nil
+	}
+
+	explicit := cfg.TLSServerName != "" || cfg.TLSCAFile != "" || cfg.TLSCertFile != "" ||
+		cfg.TLSKeyFile != "" || cfg.TLSInsecureSkipVerify || cfg.TLSClientCertificate != nil
+	if !explicit {
+		if scheme != "https" {
+			return nil, nil
+		}
+		return &tls.Config{}, nil
+	}
+
+	tlsConfig := &tls.Config{
+		ServerName:         cfg.TLSServerName,
+		InsecureSkipVerify: cfg.TLSInsecureSkipVerify,
+	}
+
+	if cfg.TLSCAFile != "" {
+		pem, err := os.ReadFile(cfg.TLSCAFile)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read tls ca file: %w", err)
+		}
+		pool := x509.NewCertPool()
+		if !pool.AppendCertsFromPEM(pem) {
+			return nil, fmt.Errorf("no certificates found in tls ca file %q", cfg.TLSCAFile)
+		}
+		tlsConfig.RootCAs = pool
+	}
+
+	switch {
+	case cfg.TLSClientCertificate != nil:
+		tlsConfig.GetClientCertificate = func(*tls.CertificateRequestInfo) (*tls.Certificate, error) {
+			cert, err := cfg.TLSClientCertificate()
+			if err != nil {
+				return nil, fmt.Errorf("failed to get tls client certificate: %w", err)
+			}
+			return cert, nil
+		}
+	case cfg.TLSCertFile != "" || cfg.TLSKeyFile != "":
+		certs := &certFiles{certFile: cfg.TLSCertFile, keyFile: cfg.TLSKeyFile}
+		if _, err := certs.load(); err != nil {
+			return nil, err
+		}
+		tlsConfig.GetClientCertificate = func(*tls.CertificateRequestInfo) (*tls.Certificate, error) {
+			return certs.load()
+		}
+	}
+
+	return tlsConfig, nil
+}
+
+// certFiles loads a client certificate from PEM files and reloads it when
+// either file changes, so certificates rotated on disk, e.g. by a service
+// mesh agent, are presented on the next handshake.
+type certFiles struct {
+	certFile string
+	keyFile  string
+
+	mu       sync.Mutex
+	cert     *tls.Certificate
+	modTimes [2]time.Time
+}
+
+func (c *certFiles) load() (*tls.Certificate, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	var modTimes [2]time.Time
+	for i, name := range []string{c.certFile, c.keyFile} {
+		info, err := os.Stat(name)
+		if err != nil {
+			return nil, fmt.Errorf("failed to load tls client certificate: %w", err)
+		}
+		modTimes[i] = info.ModTime()
+	}
+	if c.cert != nil && modTimes == c.modTimes {
+		return c.cert, nil
+	}
+
+	cert, err := tls.LoadX509KeyPair(c.certFile, c.keyFile)
+	if err != nil {
+		if c.cert != nil {
+			// The files may be mid-rotation; keep presenting the
+			// previous certificate until both are in place.
+			return c.cert, nil
+		}
+		return nil, fmt.Errorf("failed to load tls client certificate: %w", err)
+	}
+	c.cert, c.modTimes = &cert, modTimes
+
+	return c.cert, nil
+}
@@ -0,0 +1,78 @@
+package tracer
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	sdkTrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/trace"
+)
+
+var _ sdkTrace.SpanProcessor = (*traceDurationProcessor)(nil)
+
+// traceDurationProcessor marks spans for dropping when they start more than
+// max after the local root of their trace. Local roots are spans without a
+// parent or with a remote one.
+//
+// Roots older than max are forgotten, so a span with a local parent whose
+// trace is unknown belongs to an expired trace and is dropped as well.
+type traceDurationProcessor struct {
+	max time.Duration
+
+	mu        sync.Mutex
+	roots     map[trace.TraceID]time.Time
+	lastSweep time.Time
+}
+
+func newTraceDurationProcessor(max time.Duration) *traceDurationProcessor {
+	return &traceDurationProcessor{
+		max:       max,
+		roots:     make(map[trace.TraceID]time.Time),
+		lastSweep: time.Now(),
+	}
+}
+
+func (p *traceDurationProcessor) OnStart(parent context.Context, s sdkTrace.ReadWriteSpan) {
+	now := s.StartTime()
+	traceID := s.SpanContext().TraceID()
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	p.sweep(now)
+
+	if ps := s.Parent(); !ps.IsValid() || ps.IsRemote() {
+		if start, ok := p.roots[traceID]; !ok || now.Sub(start) > p.max {
+			p.roots[traceID] = now
+		}
+		return
+	}
+
+	if start, ok := p.roots[traceID]; !ok || now.Sub(start) > p.max {
+		s.SetAttributes(dropKey.Bool(true))
+	}
+}
+
+func (p *traceDurationProcessor) sweep(now time.Time) {
+	if now.Sub(p.lastSweep) < p.max {
+		return
+	}
+	p.lastSweep = now
+
+	for traceID, start := range p.roots {
+		if now.Sub(start) > p.max {
+			delete(p.roots, traceID)
+		}
+	}
+}
+
+func (p *traceDurationProcessor) OnEnd(s sdkTrace.ReadOnlySpan) {}
+
+func (p *traceDurationProcessor) Shutdown(ctx context.Context) error {
+	return nil
+}
+
+func (p *traceDurationProcessor) ForceFlush(ctx context.Context) error {
+	return nil
+}
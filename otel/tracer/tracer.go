@@ -2,32 +2,64 @@ package tracer
 
 import (
 	"context"
+	"crypto/tls"
 	"fmt"
-	"net/url"
+	"log/slog"
+	"os"
+	"slices"
+	"sync"
+	"sync/atomic"
+	"time"
 
+	"go.opentelemetry.io/contrib/propagators/aws/xray"
 	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
 	"go.opentelemetry.io/otel/exporters/otlp/otlptrace"
-	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracegrpc"
+	"go.opentelemetry.io/otel/metric"
 	"go.opentelemetry.io/otel/propagation"
-	"go.opentelemetry.io/otel/sdk/resource"
 	sdkTrace "go.opentelemetry.io/otel/sdk/trace"
-	semconv "go.opentelemetry.io/otel/semconv/v1.9.0"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
 	"go.opentelemetry.io/otel/trace"
 	"go.opentelemetry.io/otel/trace/noop"
+	"google.golang.org/grpc"
 	"google.golang.org/grpc/credentials"
+
+	"github.com/0x5w4/go-otel/otel/baggage"
 )
 
 var _ Tracer = (*otelTracer)(nil)
 
+// Tracer is returned by the constructors of this package. Depend on it
+// rather than on a concrete type to inject a tracertest.FakeTracer in tests.
 type Tracer interface {
 	Tracer() trace.Tracer
 	TracerProvider() trace.TracerProvider
+	Named(instrumentationName string, version string) trace.Tracer
+	Propagator() propagation.TextMapPropagator
+	Sampler() *DynamicSampler
+	Reload(ctx context.Context, cfg *Config) error
+	HealthCheck(ctx context.Context) error
+	LastExportStatus() ExportStatus
+	LastExportError() error
+	HandleShutdownSignals(ctx context.Context, sigs ...os.Signal) func()
+	ForceFlush(ctx context.Context) error
+	ShutdownWithTimeout(d time.Duration) error
 	Shutdown(ctx context.Context) error
 }
 
 type otelTracer struct {
 	tracer         trace.Tracer
 	tracerProvider trace.TracerProvider
+	propagator     propagation.TextMapPropagator
+	exporter       *hookExporter
+	collector      atomic.Pointer[endpointURL]
+	sampler        *DynamicSampler
+	swap           *swapExporter
+	memory         *tracetest.InMemoryExporter
+	recorder       *tracetest.SpanRecorder
+
+	namedMu sync.Mutex
+	named   map[tracerScope]trace.Tracer
 }
 
 type Config struct {
@@ -38,87 +70,666 @@ type Config struct {
 	DeploymentEnvironment string
 	Creds                 *credentials.TransportCredentials
 	Sampler               *sdkTrace.Sampler
+	UserAgent             string
+	TokenProvider         func() string
+	OnExport              func(spanCount int, err error)
+
+	// SkipPropagatorRegistration leaves the global TextMapPropagator
+	// untouched for applications that manage their own.
+	SkipPropagatorRegistration bool
+
+	// MaxAttributeCardinality caps the distinct values recorded per span
+	// attribute key. Once a key reaches the cap, spans carrying new values
+	// for it are exported without that attribute. Zero disables the guard.
+	MaxAttributeCardinality int
+
+	// MirrorEventsAsLogs emits every span event as a log record through the
+	// global LoggerProvider, correlated by trace and span ID.
+	MirrorEventsAsLogs bool
+
+	// OnQueueDepth is called every QueueDepthInterval (10s by default) with
+	// the approximate number of spans waiting in the batch span processor.
+	OnQueueDepth       func(depth int)
+	QueueDepthInterval time.Duration
+
+	// SetOKOnEnd exports spans that ended without a status as codes.Ok.
+	SetOKOnEnd bool
+
+	// DropSpanIf is called with the attributes of every ended span; spans
+	// for which it returns true are not exported. The slice is shared with
+	// the span and must not be modified.
+	DropSpanIf func(attrs []attribute.KeyValue) bool
+
+	// CorrelationIDKey is the context key under which the application stores
+	// its correlation ID. When set, the ID (a string or fmt.Stringer) is
+	// recorded on every span as the correlation.id attribute.
+	CorrelationIDKey any
+
+	// Attributes are added to the resource after the attributes derived from
+	// the fields above and take precedence over them.
+	Attributes []attribute.KeyValue
+
+	// TargetSpansPerSecond, when Sampler is nil, samples root spans with a
+	// probability adjusted every second to keep the sampled rate near the
+	// target. Child spans follow their parent's decision.
+	TargetSpansPerSecond float64
+
+	// EmitStartupSpan emits a single service.startup span right after
+	// initialization, carrying the resource and build info attributes, as
+	// a deploy marker.
+	EmitStartupSpan bool
+
+	// MaxEventsWarn logs a warning, once per span name, when a span ends
+	// with more events than this. Zero disables the check.
+	MaxEventsWarn int
+
+	// TLSServerName overrides the server name used to verify the collector
+	// certificate. Like the other TLS fields it enables TLS when Creds is
+	// nil; when Creds is set, the server name configured in those
+	// credentials applies instead.
+	TLSServerName string
+
+	// MaxTraceDuration drops spans started longer than this after the root
+	// of their trace, cutting off traces kept open by a leaked context.
+	// Zero disables the guard.
+	MaxTraceDuration time.Duration
+
+	// AttributeKeyPrefix is prepended to the keys of attributes set through
+	// SetAttributes and Attr. Keys starting with one of
+	// AttributePrefixExempt, which defaults to the semantic convention
+	// namespaces, are left alone. The prefix applies process wide and is
+	// ignored with SkipGlobalRegistration.
+	AttributeKeyPrefix    string
+	AttributePrefixExempt []string
+
+	// EnableK8sAttributesFromEnv adds k8s.pod.name, k8s.namespace.name and
+	// k8s.node.name from the POD_NAME, POD_NAMESPACE and NODE_NAME
+	// environment variables, as exposed through the downward API.
+	EnableK8sAttributesFromEnv bool
+
+	// GRPCServiceConfig is a raw JSON gRPC service config for the exporter
+	// connection, e.g. {"loadBalancingConfig":[{"round_robin":{}}]} to
+	// spread exports across all collector addresses.
+	GRPCServiceConfig string
+
+	// Protocol selects the OTLP transport, ProtocolGRPC (the default) or
+	// ProtocolHTTPProtobuf. Creds and GRPCServiceConfig only apply to gRPC.
+	Protocol string
+
+	// Headers are sent with every export, e.g. API keys, tenant IDs or basic
+	// auth. An Authorization header here takes precedence over the Bearer
+	// header derived from SecretToken, which is only sent when SecretToken
+	// is set.
+	Headers map[string]string
+
+	// AdditionalExporters receive the same spans as the OTLP exporter, each
+	// through its own batch span processor.
+	AdditionalExporters []sdkTrace.SpanExporter
+
+	// BaggageKeys names baggage members, e.g. tenant_id or user_tier,
+	// copied onto every span as attributes when the span starts, so spans
+	// of downstream services carry them too. BaggageAttributePrefix is
+	// prepended to their attribute keys.
+	BaggageKeys            []string
+	BaggageAttributePrefix string
+
+	// Propagators selects the globally registered propagators by their
+	// OTEL_PROPAGATORS names: tracecontext, baggage, b3, b3multi, jaeger and
+	// xray. It defaults to tracecontext and baggage.
+	Propagators []string
+
+	// SamplerSpec, when Sampler is nil, configures the sampler from a spec
+	// such as "parentbased_traceidratio:0.1", see ParseSampler. It takes
+	// precedence over TargetSpansPerSecond.
+	SamplerSpec string
+
+	// TailSamplingWindow enables error-biased tail sampling: spans the
+	// sampler drops are still recorded and buffered per trace for this long,
+	// and the whole trace is exported if any of its spans ends with an error
+	// status or takes longer than TailSamplingLatency. Recording every span
+	// costs more than dropping it at the head. Zero disables it.
+	TailSamplingWindow time.Duration
+
+	// TailSamplingLatency is the span duration above which tail sampling
+	// keeps a trace. Zero keeps traces for errors only.
+	TailSamplingLatency time.Duration
+
+	// ResourceDetectors enables built-in resource detectors by name: host
+	// (name, OS type, arch), process (PID, executable, Go runtime),
+	// container (ID from cgroups), k8s (as EnableK8sAttributesFromEnv), aws
+	// (EC2 instance metadata) and gcp. Detected attributes are overridden by
+	// Attributes.
+	ResourceDetectors []string
+
+	// ResourceAttributes are extra string resource attributes such as team,
+	// region or tenant. They are merged after the service attributes and
+	// detectors, and Attributes take precedence over them.
+	ResourceAttributes map[string]string
+
+	// MaxQueueSize, MaxExportBatchSize, BatchTimeout and ExportTimeout tune
+	// the batch span processors of all exporters. Zero keeps the SDK
+	// defaults.
+	MaxQueueSize       int
+	MaxExportBatchSize int
+	BatchTimeout       time.Duration
+	ExportTimeout      time.Duration
+
+	// Retry configures how the OTLP exporter retries failed exports. Nil
+	// keeps the exporter defaults: retries enabled, starting at 5s, backing
+	// off up to 30s and giving up after 1m. Use NoRetry() for shutdown paths
+	// that must not block.
+	Retry *RetryConfig
+
+	// TLSCAFile, TLSCertFile and TLSKeyFile are PEM files with the CA that
+	// verifies the collector and the client certificate and key presented
+	// to it. TLSInsecureSkipVerify disables verification of the collector
+	// certificate. Setting any of them enables TLS for both protocols
+	// unless the endpoint scheme is http://. The client certificate is reloaded
+	// when its files change, so it can be rotated without a restart.
+	TLSCAFile             string
+	TLSCertFile           string
+	TLSKeyFile            string
+	TLSInsecureSkipVerify bool
+
+	// TLSClientCertificate, when set, is called on every TLS handshake for
+	// the client certificate presented to the collector, for mutual TLS
+	// with certificates from a secret store or a rotating issuer. It takes
+	// precedence over TLSCertFile and TLSKeyFile and enables TLS like them.
+	TLSClientCertificate func() (*tls.Certificate, error)
+
+	// Insecure forces a plaintext connection to the collector. Without it
+	// and without TLS settings, TLS is used for https:// endpoints only.
+	Insecure bool
+
+	// SkipGlobalRegistration leaves the global tracer provider and
+	// propagator untouched, for binaries running several tracers and for
+	// tests. Use the Tracer, TracerProvider and Propagator of the returned
+	// tracer instead.
+	SkipGlobalRegistration bool
+
+	// RedactAttributeKeys drops span and event attributes whose keys match
+	// one of these glob patterns, e.g. "http.request.header.authorization"
+	// or "*password*", before export. HashAttributeKeys replaces the values
+	// of matching attributes with their SHA-256 instead, keeping them
+	// comparable. Patterns are matched case-insensitively.
+	RedactAttributeKeys []string
+	HashAttributeKeys   []string
+
+	// NormalizeRules rewrite span names and the http.target and url.path
+	// attributes before export to keep their cardinality low. Use
+	// DefaultNormalizeRules to collapse numeric IDs and UUIDs into {id}.
+	NormalizeRules []NormalizeRule
+
+	// SpanLimits caps the attributes, attribute value length, events and
+	// links recorded per span. Start from sdkTrace.NewSpanLimits(), which
+	// honours the OTEL_SPAN_*_LIMIT environment variables, since zero
+	// fields are taken literally. Nil keeps the SDK defaults.
+	SpanLimits *sdkTrace.SpanLimits
+
+	// Compression selects the compression of export requests,
+	// CompressionNone (the default) or CompressionGzip, which trades some
+	// CPU for much less collector traffic.
+	Compression string
+
+	// TokenSource supplies the bearer token of every export request, for
+	// short-lived OAuth or OIDC tokens. It takes precedence over
+	// TokenProvider and SecretToken. A failing TokenSource fails the export.
+	TokenSource TokenSource
+
+	// XRayCompatible generates trace IDs that embed their start time, as AWS
+	// X-Ray requires, and adds the xray propagator to Propagators, so traces
+	// can be continued across ALB and services traced with X-Ray.
+	XRayCompatible bool
+
+	// SpanProcessors are registered with the tracer provider right after
+	// the exporting batcher and are shut down with the provider. They see
+	// every span, including those dropped before export, but only observe
+	// them: attributes set in OnStart are exported, while nothing done in
+	// OnEnd can change or drop an exported span. Use DropSpanNames or
+	// ExportFilter to keep spans from being exported.
+	SpanProcessors []sdkTrace.SpanProcessor
+
+	// OnDroppedSpans is called every QueueDepthInterval, and on shutdown,
+	// with the number of sampled spans dropped since the previous call
+	// because the export queue of MaxQueueSize spans was full.
+	OnDroppedSpans func(count int)
+
+	// SelfTelemetry receives metrics about the export pipeline: spans
+	// started by sampling decision, spans exported and export duration by
+	// result, spans dropped on a full queue and the queue size.
+	SelfTelemetry metric.MeterProvider
+
+	// SpanMetrics receives request count, error count and duration metrics
+	// derived from every ended span by name, kind and status, plus the
+	// span attributes named in SpanMetricsAttributes, e.g. http.route.
+	// Spans the sampler drops are still recorded, though not exported, so
+	// the metrics cover all traffic at the cost of recording every span.
+	// Names, attributes and status are taken after NormalizeRules,
+	// redaction and SetOKOnEnd; spans removed by DropSpanIf or ExportFilter
+	// are not counted.
+	SpanMetrics           metric.MeterProvider
+	SpanMetricsAttributes []string
+
+	// DropSpanNames never samples spans whose name matches one of these
+	// path.Match patterns, e.g. "GET /healthz", so infrastructure noise
+	// uses neither sampling budget nor backend quota. At sampling time the
+	// patterns are also matched against "METHOD /path" from the HTTP
+	// attributes, for server spans named after their route only when they
+	// end; spans whose final name matches are dropped before export.
+	DropSpanNames []string
+
+	// ExportFilter drops ended spans by instrumentation scope and span
+	// name right before export, e.g. to leave out the spans of a noisy
+	// third-party library. Unlike DropSpanNames it does not affect
+	// sampling: children of a filtered span are still exported.
+	ExportFilter ExportFilter
+
+	// SamplingRules sample root spans whose name or HTTP request matches a
+	// rule with the ratio of the first such rule, e.g. 1 for
+	// "POST /checkout" and 0.01 for "GET /search"; other root spans go to
+	// the configured sampler. Child spans follow their parent. Changes
+	// through otelTracer.Sampler only affect spans no rule matches.
+	SamplingRules []SamplingRule
+
+	// HeaderProvider is called on every export request for headers that
+	// depend on runtime state, such as the tenant or shard a multi-tenant
+	// collector routes by. Its headers are added after Headers and, over
+	// HTTP, replace static headers of the same name. A failing
+	// HeaderProvider fails the export.
+	HeaderProvider HeaderProvider
+
+	// GRPCDialOptions are applied to the gRPC exporter connection after the
+	// options derived from the fields above, e.g. grpc.WithKeepaliveParams
+	// to keep idle connections alive across NAT timeouts, resolvers or
+	// interceptors. They are ignored for ProtocolHTTPProtobuf.
+	GRPCDialOptions []grpc.DialOption
+
+	// IDGenerator generates the trace and span IDs of new spans, e.g. a
+	// deterministic generator in tests or one embedding a shard prefix. It
+	// takes precedence over the generator of XRayCompatible. Nil keeps the
+	// random generator of the SDK.
+	IDGenerator sdkTrace.IDGenerator
+
+	// FailoverURL is a secondary collector endpoint, reached with the same
+	// protocol, credentials and TLS settings as ExporterURL. After
+	// FailoverThreshold (3 by default) consecutive failed exports to the
+	// primary, exports switch to it, starting with the failed batch. With
+	// FailoverRetryPrimary set, the primary is tried again that long after
+	// the switch and used again once an export to it succeeds. Retry
+	// decides how long an export takes to fail.
+	FailoverURL          string
+	FailoverThreshold    int
+	FailoverRetryPrimary time.Duration
+
+	// CircuitBreakerThreshold opens a circuit breaker around the exporter
+	// after this many consecutive failed exports: batches are then dropped
+	// at once with ErrExportCircuitOpen instead of waiting out the retries
+	// against a dead collector, and the export queue keeps draining. After
+	// CircuitBreakerCooldown (30s by default) a single export probes the
+	// collector and closes the circuit on success. Zero disables it.
+	CircuitBreakerThreshold int
+	CircuitBreakerCooldown  time.Duration
+
+	// SpilloverDir enables a disk buffer in this directory for batches the
+	// collector did not accept and spans dropped from a full queue. They are
+	// replayed once exports succeed again, also by the next process using
+	// the directory. SpilloverMaxBytes, 64 MiB by default, bounds the
+	// buffer by dropping its oldest batches; SpilloverRetryInterval, 15
+	// seconds by default, is how often a replay is attempted.
+	SpilloverDir           string
+	SpilloverMaxBytes      int64
+	SpilloverRetryInterval time.Duration
+
+	// ProfilingLabels sets pprof labels carrying the trace ID, span ID and
+	// span name of the sampled spans of this tracer started through
+	// StartSpan on the goroutine running them, so CPU profiles, including
+	// those collected by Pyroscope, can be matched with slow traces.
+	ProfilingLabels bool
+
+	// Debug logs every exported span through Logger with its name, kind,
+	// IDs, duration, status and attributes, after redaction and the other
+	// rewrites, to verify instrumentation without the trace backend.
+	Debug bool
+
+	// Logger receives warnings from the span processors and the span logs
+	// of Debug. It defaults to slog.Default().
+	Logger *slog.Logger
 }
 
-func InitTracer(ctx context.Context, cfg *Config) (*otelTracer, error) {
-	if cfg.ExporterURL == "" {
-		return nil, fmt.Errorf("endpoint is missing in the otlp tracer configuration")
+// InitTracer sets up an OTLP exporting tracer provider and registers it
+// globally. It accepts a *Config, options, or a *Config followed by options
+// that adjust it.
+func InitTracer(ctx context.Context, opts ...Option) (Tracer, error) {
+	t, err := initTracer(ctx, false, opts...)
+	if err != nil {
+		return nil, err
 	}
 
-	if cfg.ServiceName == "" {
-		return nil, fmt.Errorf("service name is missing in the otlp tracer configuration")
-	}
+	return t, nil
+}
 
-	u, err := url.Parse(cfg.ExporterURL)
+// NewLazy is InitTracer without connecting to the collector: the OTLP
+// exporter is only created for the first export, so binaries that set up
+// tracing before parsing flags or reading secrets do not block on the
+// collector at startup. The configuration is still validated up front.
+func NewLazy(cfg *Config, opts ...Option) (Tracer, error) {
+	t, err := initTracer(context.Background(), true, append([]Option{cfg}, opts...)...)
 	if err != nil {
-		return nil, fmt.Errorf("invalid exporter URL: %w", err)
+		return nil, err
+	}
+
+	return t, nil
+}
+
+func initTracer(ctx context.Context, lazy bool, opts ...Option) (*otelTracer, error) {
+	cfg := new(Config)
+	for _, opt := range opts {
+		opt.apply(cfg)
+	}
+
+	if err := cfg.Validate(); err != nil {
+		return nil, err
+	}
+	serviceName := resolveServiceName(cfg)
+
+	var otlpExporter sdkTrace.SpanExporter = &lazyExporter{cfg: cfg}
+	if !lazy {
+		var err error
+		if otlpExporter, err = newExporter(ctx, cfg); err != nil {
+			return nil, err
+		}
 	}
 
-	endpoint := u.Host
-	if u.Scheme == "http" {
-		cfg.Creds = nil
+	swap := &swapExporter{current: otlpExporter}
+	t, err := newTracer(ctx, cfg, serviceName, swap)
+	if err != nil {
+		return nil, err
 	}
+	if collector, err := parseEndpoint(cfg.ExporterURL); err == nil {
+		t.collector.Store(&collector)
+	}
+	t.swap = swap
+
+	return t, nil
+}
 
-	var secureOption otlptracegrpc.Option
-	if cfg.Creds != nil {
-		secureOption = otlptracegrpc.WithTLSCredentials(*cfg.Creds)
-	} else {
-		secureOption = otlptracegrpc.WithInsecure()
+func newOTLPExporter(ctx context.Context, cfg *Config) (*otlptrace.Exporter, error) {
+	client, err := newClient(cfg)
+	if err != nil {
+		return nil, err
 	}
 
-	exporter, err := otlptrace.New(
-		ctx,
-		otlptracegrpc.NewClient(
-			otlptracegrpc.WithEndpoint(endpoint),
-			secureOption,
-			otlptracegrpc.WithHeaders(map[string]string{
-				"Authorization": fmt.Sprintf("Bearer %s", cfg.SecretToken),
-			}),
-		),
-	)
+	exporter, err := otlptrace.New(ctx, client)
 	if err != nil {
 		return nil, fmt.Errorf("failed to create otlp exporter: %w", err)
 	}
 
-	resource, err := resource.New(
-		ctx,
-		resource.WithAttributes(
-			semconv.ServiceNameKey.String(cfg.ServiceName),
-			semconv.ServiceVersionKey.String(cfg.ServiceVersion),
-			semconv.DeploymentEnvironmentKey.String(cfg.DeploymentEnvironment),
-			semconv.TelemetrySDKLanguageKey.String("go"),
-		),
-	)
+	return exporter, nil
+}
+
+// newTracer builds the tracer provider around base, the exporter selected
+// by the Init function, and registers it globally.
+func newTracer(ctx context.Context, cfg *Config, serviceName string, base sdkTrace.SpanExporter) (*otelTracer, error) {
+	propagator, err := newPropagator(resolvePropagators(cfg))
+	if err != nil {
+		return nil, err
+	}
+
+	logger := cfg.Logger
+	if logger == nil {
+		logger = slog.Default()
+	}
+
+	if cfg.CircuitBreakerThreshold > 0 {
+		base = newBreakerExporter(base, cfg.CircuitBreakerThreshold, cfg.CircuitBreakerCooldown, logger)
+	}
+
+	hook := &hookExporter{
+		SpanExporter: base,
+		onExport:     cfg.OnExport,
+	}
+
+	var exporter sdkTrace.SpanExporter = hook
+	var spillover *spilloverExporter
+	if cfg.SpilloverDir != "" {
+		if spillover, err = newSpilloverExporter(hook, base, cfg, logger); err != nil {
+			return nil, err
+		}
+		exporter = spillover
+	}
+
+	res, err := newResource(ctx, cfg, serviceName)
+	if err != nil {
+		return nil, err
+	}
+
+	sampler, err := newSampler(cfg)
 	if err != nil {
-		return nil, fmt.Errorf("failed to create otlp resource: %w", err)
+		return nil, err
 	}
 
-	var sampler sdkTrace.Sampler = sdkTrace.AlwaysSample()
-	if cfg.Sampler != nil {
-		sampler = *cfg.Sampler
+	dynamic := NewDynamicSampler(sampler)
+	sampler = dynamic
+	if len(cfg.SamplingRules) > 0 {
+		if sampler, err = newRuleSampler(cfg.SamplingRules, sampler); err != nil {
+			return nil, err
+		}
+	}
+	if cfg.TailSamplingWindow > 0 || cfg.SpanMetrics != nil {
+		sampler = recordOnlySampler{Sampler: sampler}
+	}
+	dropNames, err := newSpanNamePatterns(cfg.DropSpanNames)
+	if err != nil {
+		return nil, err
+	}
+	if len(dropNames) > 0 {
+		sampler = nameDropSampler{Sampler: sampler, patterns: dropNames}
 	}
 
-	tp := sdkTrace.NewTracerProvider(
+	var batchOptions []sdkTrace.BatchSpanProcessorOption
+	if cfg.MaxQueueSize > 0 {
+		batchOptions = append(batchOptions, sdkTrace.WithMaxQueueSize(cfg.MaxQueueSize))
+	}
+	if cfg.MaxExportBatchSize > 0 {
+		batchOptions = append(batchOptions, sdkTrace.WithMaxExportBatchSize(cfg.MaxExportBatchSize))
+	}
+	if cfg.BatchTimeout > 0 {
+		batchOptions = append(batchOptions, sdkTrace.WithBatchTimeout(cfg.BatchTimeout))
+	}
+	if cfg.ExportTimeout > 0 {
+		batchOptions = append(batchOptions, sdkTrace.WithExportTimeout(cfg.ExportTimeout))
+	}
+
+	mainBatchOptions := batchOptions
+	var queue *queueDepth
+	if cfg.OnQueueDepth != nil || cfg.OnDroppedSpans != nil || cfg.SelfTelemetry != nil || spillover != nil {
+		maxSize := int64(sdkTrace.DefaultMaxQueueSize)
+		if cfg.MaxQueueSize > 0 {
+			maxSize = int64(cfg.MaxQueueSize)
+		}
+		batchSize := int64(sdkTrace.DefaultMaxExportBatchSize)
+		if cfg.MaxExportBatchSize > 0 {
+			batchSize = int64(cfg.MaxExportBatchSize)
+		}
+		queue = &queueDepth{maxSize: maxSize}
+		mainBatchOptions = slices.Concat(batchOptions, []sdkTrace.BatchSpanProcessorOption{
+			sdkTrace.WithMaxQueueSize(int(maxSize + batchSize)),
+		})
+	}
+
+	var metrics *pipelineMetrics
+	if cfg.SelfTelemetry != nil {
+		if metrics, err = newPipelineMetrics(cfg.SelfTelemetry, queue); err != nil {
+			return nil, err
+		}
+		exporter = &pipelineMetricsExporter{SpanExporter: exporter, metrics: metrics}
+		sampler = &pipelineMetricsSampler{Sampler: sampler, metrics: metrics}
+	}
+	if queue != nil {
+		exporter = &queueDepthExporter{SpanExporter: exporter, queue: queue}
+	}
+
+	var processor sdkTrace.SpanProcessor = sdkTrace.NewBatchSpanProcessor(exporter, mainBatchOptions...)
+	if queue != nil {
+		queueProcessor := newQueueDepthProcessor(processor, queue, cfg.QueueDepthInterval, cfg.OnQueueDepth, cfg.OnDroppedSpans)
+		if spillover != nil {
+			queueProcessor.overflow = spillover.overflow
+		}
+		processor = queueProcessor
+	}
+	if len(cfg.AdditionalExporters) > 0 || cfg.Debug {
+		batchers := fanOutProcessor{processor}
+		for _, e := range cfg.AdditionalExporters {
+			batchers = append(batchers, sdkTrace.NewBatchSpanProcessor(e, batchOptions...))
+		}
+		if cfg.Debug {
+			batchers = append(batchers, &debugProcessor{logger: logger})
+		}
+		processor = batchers
+	}
+	if cfg.MaxAttributeCardinality > 0 {
+		processor = newCardinalityProcessor(processor, cfg.MaxAttributeCardinality, logger)
+	}
+	if cfg.TailSamplingWindow > 0 {
+		processor = newTailSamplingProcessor(processor, cfg.TailSamplingWindow, cfg.TailSamplingLatency)
+	}
+	if cfg.SpanMetrics != nil {
+		if processor, err = newSpanMetricsProcessor(processor, cfg.SpanMetrics, cfg.SpanMetricsAttributes); err != nil {
+			return nil, err
+		}
+	}
+	if cfg.SetOKOnEnd {
+		processor = &okStatusProcessor{SpanProcessor: processor}
+	}
+
+	if len(cfg.NormalizeRules) > 0 {
+		processor = &normalizeProcessor{SpanProcessor: processor, rules: cfg.NormalizeRules}
+	}
+	if len(cfg.RedactAttributeKeys) > 0 || len(cfg.HashAttributeKeys) > 0 {
+		if processor, err = newRedactProcessor(processor, cfg.RedactAttributeKeys, cfg.HashAttributeKeys); err != nil {
+			return nil, err
+		}
+	}
+
+	dropRules := []func(sdkTrace.ReadOnlySpan) bool{isDropped}
+	if cfg.DropSpanIf != nil {
+		dropRules = append(dropRules, func(s sdkTrace.ReadOnlySpan) bool {
+			return cfg.DropSpanIf(s.Attributes())
+		})
+	}
+	if len(dropNames) > 0 {
+		dropRules = append(dropRules, func(s sdkTrace.ReadOnlySpan) bool {
+			return dropNames.match(s.Name())
+		})
+	}
+	if cfg.ExportFilter.enabled() {
+		filter, err := newExportFilter(cfg.ExportFilter)
+		if err != nil {
+			return nil, err
+		}
+		dropRules = append(dropRules, filter.drop)
+	}
+	processor = &filterProcessor{SpanProcessor: processor, rules: dropRules}
+
+	providerOptions := []sdkTrace.TracerProviderOption{
 		sdkTrace.WithSampler(sampler),
-		sdkTrace.WithBatcher(exporter),
-		sdkTrace.WithResource(resource),
-	)
-	otel.SetTracerProvider(tp)
+		sdkTrace.WithResource(res),
+	}
+	if cfg.SpanLimits != nil {
+		providerOptions = append(providerOptions, sdkTrace.WithRawSpanLimits(*cfg.SpanLimits))
+	}
+	switch {
+	case cfg.IDGenerator != nil:
+		providerOptions = append(providerOptions, sdkTrace.WithIDGenerator(cfg.IDGenerator))
+	case cfg.XRayCompatible:
+		providerOptions = append(providerOptions, sdkTrace.WithIDGenerator(xray.NewIDGenerator()))
+	}
+	if cfg.MaxTraceDuration > 0 {
+		providerOptions = append(providerOptions, sdkTrace.WithSpanProcessor(newTraceDurationProcessor(cfg.MaxTraceDuration)))
+	}
+	providerOptions = append(providerOptions, sdkTrace.WithSpanProcessor(&requestAttributesProcessor{}))
+	if len(cfg.BaggageKeys) > 0 {
+		providerOptions = append(providerOptions, sdkTrace.WithSpanProcessor(baggage.NewPrefixedSpanProcessor(cfg.BaggageAttributePrefix, cfg.BaggageKeys...)))
+	}
+	if cfg.CorrelationIDKey != nil {
+		providerOptions = append(providerOptions, sdkTrace.WithSpanProcessor(&correlationIDProcessor{contextKey: cfg.CorrelationIDKey}))
+	}
+	providerOptions = append(providerOptions, sdkTrace.WithSpanProcessor(processor))
+	for _, p := range cfg.SpanProcessors {
+		providerOptions = append(providerOptions, sdkTrace.WithSpanProcessor(p))
+	}
+	if cfg.MaxEventsWarn > 0 {
+		providerOptions = append(providerOptions, sdkTrace.WithSpanProcessor(&eventCountProcessor{limit: cfg.MaxEventsWarn, logger: logger}))
+	}
+	if cfg.MirrorEventsAsLogs {
+		providerOptions = append(providerOptions, sdkTrace.WithSpanProcessor(newEventLogProcessor()))
+	}
 
-	otel.SetTextMapPropagator(
-		propagation.NewCompositeTextMapPropagator(
-			propagation.TraceContext{},
-			propagation.Baggage{},
-		),
-	)
+	tp := sdkTrace.NewTracerProvider(providerOptions...)
+	if cfg.ProfilingLabels {
+		profilingProviders.Store(tp, struct{}{})
+	}
+	if !cfg.SkipGlobalRegistration {
+		// The prefix is process wide like the provider, so only the global
+		// tracer sets it.
+		setAttributeKeyPrefix(cfg.AttributeKeyPrefix, cfg.AttributePrefixExempt)
+		otel.SetTracerProvider(tp)
+		if !cfg.SkipPropagatorRegistration {
+			otel.SetTextMapPropagator(propagator)
+		}
+	}
 
-	return &otelTracer{
-		tracer:         otel.Tracer(fmt.Sprintf("%s-tracer", cfg.ServiceName)),
+	t := &otelTracer{
+		tracer:         tp.Tracer(fmt.Sprintf("%s-tracer", serviceName)),
 		tracerProvider: tp,
-	}, nil
+		propagator:     propagator,
+		exporter:       hook,
+		sampler:        dynamic,
+	}
+
+	if cfg.EmitStartupSpan {
+		_, span := t.tracer.Start(ctx, "service.startup",
+			trace.WithNewRoot(),
+			trace.WithAttributes(res.Attributes()...),
+			trace.WithAttributes(buildInfoAttributes()...),
+		)
+		span.End()
+	}
+
+	return t, nil
+}
+
+// newSampler picks the sampler from, in order of precedence, Sampler,
+// SamplerSpec and TargetSpansPerSecond, and samples everything otherwise.
+func newSampler(cfg *Config) (sdkTrace.Sampler, error) {
+	switch {
+	case cfg.Sampler != nil:
+		return *cfg.Sampler, nil
+	case cfg.SamplerSpec != "":
+		return ParseSampler(cfg.SamplerSpec)
+	case cfg.TargetSpansPerSecond > 0:
+		return sdkTrace.ParentBased(newAdaptiveSampler(cfg.TargetSpansPerSecond)), nil
+	default:
+		return sdkTrace.AlwaysSample(), nil
+	}
+}
+
+// resolveServiceName picks the service name from, in order of precedence,
+// Config.ServiceName, the OTEL_SERVICE_NAME environment variable and the
+// main module path in the build info.
+func resolveServiceName(cfg *Config) string {
+	if cfg.ServiceName != "" {
+		return cfg.ServiceName
+	}
+
+	if name := os.Getenv("OTEL_SERVICE_NAME"); name != "" {
+		return name
+	}
+
+	return buildInfoModuleName()
 }
 
-func InitNoopTracer(ctx context.Context) (*otelTracer, error) {
+func InitNoopTracer(ctx context.Context) (Tracer, error) {
 	tp := noop.NewTracerProvider()
 	otel.SetTracerProvider(tp)
 
@@ -144,8 +755,94 @@ func (t *otelTracer) TracerProvider() trace.TracerProvider {
 	return nil
 }
 
+type tracerScope struct {
+	name    string
+	version string
+}
+
+// Named returns a tracer of the provider with its own instrumentation scope,
+// usually the import path of a library or component, so backends can break
+// spans down by it. Tracers are cached per name and version.
+func (t *otelTracer) Named(instrumentationName string, version string) trace.Tracer {
+	if t.tracerProvider == nil {
+		return nil
+	}
+
+	t.namedMu.Lock()
+	defer t.namedMu.Unlock()
+
+	scope := tracerScope{name: instrumentationName, version: version}
+	if tr, ok := t.named[scope]; ok {
+		return tr
+	}
+
+	var opts []trace.TracerOption
+	if version != "" {
+		opts = append(opts, trace.WithInstrumentationVersion(version))
+	}
+	tr := t.tracerProvider.Tracer(instrumentationName, opts...)
+
+	if t.named == nil {
+		t.named = make(map[tracerScope]trace.Tracer)
+	}
+	t.named[scope] = tr
+
+	return tr
+}
+
+// Sampler returns the sampler of the tracer, which can be changed while the
+// tracer runs. It is nil for tracers not created by InitTracer.
+func (t *otelTracer) Sampler() *DynamicSampler {
+	return t.sampler
+}
+
+// Propagator returns the propagator built from Config.Propagators, or the
+// global one for tracers that were not configured with one.
+func (t *otelTracer) Propagator() propagation.TextMapPropagator {
+	if t.propagator != nil {
+		return t.propagator
+	}
+
+	return otel.GetTextMapPropagator()
+}
+
+// LastExportError returns the error of the most recent export, or nil if it
+// succeeded or nothing has been exported yet.
+func (t *otelTracer) LastExportError() error {
+	if t.exporter == nil {
+		return nil
+	}
+
+	return t.exporter.lastStatus().Err
+}
+
+// ForceFlush exports all ended spans that have not been exported yet.
+func (t *otelTracer) ForceFlush(ctx context.Context) error {
+	if tp, ok := t.tracerProvider.(*sdkTrace.TracerProvider); ok {
+		if err := tp.ForceFlush(ctx); err != nil {
+			return fmt.Errorf("failed to flush tracer provider: %w", err)
+		}
+	}
+
+	return nil
+}
+
+// ShutdownWithTimeout flushes pending spans and shuts the tracer down,
+// giving up after d.
+func (t *otelTracer) ShutdownWithTimeout(d time.Duration) error {
+	ctx, cancel := context.WithTimeout(context.Background(), d)
+	defer cancel()
+
+	if err := t.ForceFlush(ctx); err != nil {
+		return err
+	}
+
+	return t.Shutdown(ctx)
+}
+
 func (t *otelTracer) Shutdown(ctx context.Context) error {
 	if tp, ok := t.tracerProvider.(*sdkTrace.TracerProvider); ok {
+		profilingProviders.Delete(tp)
 		if err := tp.Shutdown(ctx); err != nil {
 			return fmt.Errorf("failed to shutdown tracer provider: %w", err)
 		}
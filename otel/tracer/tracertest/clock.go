@@ -0,0 +1,99 @@
+package tracertest
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"go.opentelemetry.io/otel/trace"
+)
+
+// Clock is the time source of the span timestamps of a FakeTracer.
+type Clock interface {
+	Now() time.Time
+}
+
+// ManualClock is a Clock that only moves when told to, for golden files and
+// exact duration assertions.
+type ManualClock struct {
+	mu  sync.Mutex
+	now time.Time
+}
+
+// NewManualClock returns a ManualClock standing at now.
+func NewManualClock(now time.Time) *ManualClock {
+	return &ManualClock{now: now}
+}
+
+func (c *ManualClock) Now() time.Time {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return c.now
+}
+
+// Advance moves the clock forward by d.
+func (c *ManualClock) Advance(d time.Duration) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.now = c.now.Add(d)
+}
+
+// Set moves the clock to now.
+func (c *ManualClock) Set(now time.Time) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.now = now
+}
+
+var _ trace.TracerProvider = (*clockProvider)(nil)
+
+// clockProvider stamps the spans of its tracers with the time of clock,
+// unless the caller passes a timestamp of its own.
+type clockProvider struct {
+	trace.TracerProvider
+	clock Clock
+}
+
+func (p *clockProvider) Tracer(name string, opts ...trace.TracerOption) trace.Tracer {
+	return &clockTracer{Tracer: p.TracerProvider.Tracer(name, opts...), clock: p.clock}
+}
+
+type clockTracer struct {
+	trace.Tracer
+	clock Clock
+}
+
+func (t *clockTracer) Start(ctx context.Context, name string, opts ...trace.SpanStartOption) (context.Context, trace.Span) {
+	// Options apply in order, so a timestamp in opts wins.
+	opts = append([]trace.SpanStartOption{trace.WithTimestamp(t.clock.Now())}, opts...)
+	ctx, span := t.Tracer.Start(ctx, name, opts...)
+
+	// The span in ctx is replaced too, for code ending the span it gets
+	// from trace.SpanFromContext.
+	stamped := &clockSpan{Span: span, clock: t.clock}
+	return trace.ContextWithSpan(ctx, stamped), stamped
+}
+
+type clockSpan struct {
+	trace.Span
+	clock Clock
+}
+
+func (s *clockSpan) End(opts ...trace.SpanEndOption) {
+	s.Span.End(append([]trace.SpanEndOption{trace.WithTimestamp(s.clock.Now())}, opts...)...)
+}
+
+func (s *clockSpan) AddEvent(name string, opts ...trace.EventOption) {
+	s.Span.AddEvent(name, append([]trace.EventOption{trace.WithTimestamp(s.clock.Now())}, opts...)...)
+}
+
+func (s *clockSpan) RecordError(err error, opts ...trace.EventOption) {
+	s.Span.RecordError(err, append([]trace.EventOption{trace.WithTimestamp(s.clock.Now())}, opts...)...)
+}
+
+func (s *clockSpan) TracerProvider() trace.TracerProvider {
+	return &clockProvider{TracerProvider: s.Span.TracerProvider(), clock: s.clock}
+}
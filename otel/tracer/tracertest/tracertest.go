@@ -0,0 +1,191 @@
+package tracertest
+
+import (
+	"context"
+	"errors"
+	"os"
+	"sync"
+	"time"
+
+	"go.opentelemetry.io/otel/propagation"
+	sdkTrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/trace"
+
+	"github.com/0x5w4/go-otel/otel/tracer"
+)
+
+var _ tracer.Tracer = (*FakeTracer)(nil)
+
+// FakeTracer is an in-memory tracer.Tracer for unit tests. It never touches
+// the global OpenTelemetry state and records every span it starts.
+type FakeTracer struct {
+	tracer         trace.Tracer
+	tracerProvider trace.TracerProvider
+	sdkProvider    *sdkTrace.TracerProvider
+	sampler        *tracer.DynamicSampler
+	recorder       *recorder
+	clock          Clock
+}
+
+// Option configures a FakeTracer.
+type Option func(f *FakeTracer)
+
+// WithClock stamps the start and end of spans and their events with the
+// time of clock, so recorded timestamps and durations are deterministic.
+// Timestamps passed explicitly with trace.WithTimestamp are kept.
+func WithClock(clock Clock) Option {
+	return func(f *FakeTracer) {
+		f.clock = clock
+	}
+}
+
+// NewFakeTracer returns a FakeTracer that samples and records every span.
+func NewFakeTracer(opts ...Option) *FakeTracer {
+	f := new(FakeTracer)
+	for _, opt := range opts {
+		opt(f)
+	}
+
+	f.recorder = new(recorder)
+	f.sampler = tracer.NewDynamicSampler(sdkTrace.AlwaysSample())
+	f.sdkProvider = sdkTrace.NewTracerProvider(
+		sdkTrace.WithSampler(f.sampler),
+		sdkTrace.WithSpanProcessor(f.recorder),
+	)
+	f.tracerProvider = f.sdkProvider
+	if f.clock != nil {
+		f.tracerProvider = &clockProvider{TracerProvider: f.sdkProvider, clock: f.clock}
+	}
+	f.tracer = f.tracerProvider.Tracer("fake-tracer")
+
+	return f
+}
+
+func (f *FakeTracer) Tracer() trace.Tracer {
+	return f.tracer
+}
+
+func (f *FakeTracer) TracerProvider() trace.TracerProvider {
+	return f.tracerProvider
+}
+
+func (f *FakeTracer) Named(instrumentationName string, version string) trace.Tracer {
+	return f.tracerProvider.Tracer(instrumentationName, trace.WithInstrumentationVersion(version))
+}
+
+// Propagator returns the W3C trace context and baggage propagators.
+func (f *FakeTracer) Propagator() propagation.TextMapPropagator {
+	return propagation.NewCompositeTextMapPropagator(propagation.TraceContext{}, propagation.Baggage{})
+}
+
+// Sampler returns the sampler of the tracer, which samples everything until
+// it is changed.
+func (f *FakeTracer) Sampler() *tracer.DynamicSampler {
+	return f.sampler
+}
+
+// Reload always fails, a FakeTracer has no exporter to reload.
+func (f *FakeTracer) Reload(ctx context.Context, cfg *tracer.Config) error {
+	return errors.New("fake tracer cannot be reloaded")
+}
+
+// HealthCheck always reports healthy.
+func (f *FakeTracer) HealthCheck(ctx context.Context) error {
+	return nil
+}
+
+func (f *FakeTracer) LastExportStatus() tracer.ExportStatus {
+	return tracer.ExportStatus{}
+}
+
+func (f *FakeTracer) LastExportError() error {
+	return nil
+}
+
+// HandleShutdownSignals installs no handler, so tests are not shut down by
+// signals. The returned func does nothing.
+func (f *FakeTracer) HandleShutdownSignals(ctx context.Context, sigs ...os.Signal) func() {
+	return func() {}
+}
+
+func (f *FakeTracer) ForceFlush(ctx context.Context) error {
+	return f.sdkProvider.ForceFlush(ctx)
+}
+
+func (f *FakeTracer) ShutdownWithTimeout(d time.Duration) error {
+	ctx, cancel := context.WithTimeout(context.Background(), d)
+	defer cancel()
+
+	return f.Shutdown(ctx)
+}
+
+func (f *FakeTracer) Shutdown(ctx context.Context) error {
+	return f.sdkProvider.Shutdown(ctx)
+}
+
+// StartedSpans returns the spans started so far, in start order.
+func (f *FakeTracer) StartedSpans() []sdkTrace.ReadOnlySpan {
+	f.recorder.mu.Lock()
+	defer f.recorder.mu.Unlock()
+
+	return append([]sdkTrace.ReadOnlySpan(nil), f.recorder.started...)
+}
+
+// EndedSpans returns the spans ended so far, in end order.
+func (f *FakeTracer) EndedSpans() []sdkTrace.ReadOnlySpan {
+	f.recorder.mu.Lock()
+	defer f.recorder.mu.Unlock()
+
+	return append([]sdkTrace.ReadOnlySpan(nil), f.recorder.ended...)
+}
+
+// SpanNames returns the names of the started spans, in start order.
+func (f *FakeTracer) SpanNames() []string {
+	started := f.StartedSpans()
+
+	names := make([]string, 0, len(started))
+	for _, s := range started {
+		names = append(names, s.Name())
+	}
+
+	return names
+}
+
+// Reset forgets all recorded spans.
+func (f *FakeTracer) Reset() {
+	f.recorder.mu.Lock()
+	defer f.recorder.mu.Unlock()
+
+	f.recorder.started = nil
+	f.recorder.ended = nil
+}
+
+var _ sdkTrace.SpanProcessor = (*recorder)(nil)
+
+type recorder struct {
+	mu      sync.Mutex
+	started []sdkTrace.ReadOnlySpan
+	ended   []sdkTrace.ReadOnlySpan
+}
+
+func (r *recorder) OnStart(parent context.Context, s sdkTrace.ReadWriteSpan) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.started = append(r.started, s)
+}
+
+func (r *recorder) OnEnd(s sdkTrace.ReadOnlySpan) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.ended = append(r.ended, s)
+}
+
+func (r *recorder) Shutdown(ctx context.Context) error {
+	return nil
+}
+
+func (r *recorder) ForceFlush(ctx context.Context) error {
+	return nil
+}
@@ -0,0 +1,98 @@
+package tracer
+
+import (
+	"fmt"
+	"net/http"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/semconv/v1.20.0/httpconv"
+	"go.opentelemetry.io/otel/trace"
+)
+
+var _ http.RoundTripper = (*transport)(nil)
+
+type transport struct {
+	tracer     trace.Tracer
+	propagator propagation.TextMapPropagator
+	base       http.RoundTripper
+}
+
+// NewTransport wraps base so that every outgoing request is recorded as a
+// client span and carries the propagation headers of that span, injected by
+// the propagator of t. A nil base uses http.DefaultTransport.
+func NewTransport(t Tracer, base http.RoundTripper) http.RoundTripper {
+	if base == nil {
+		base = http.DefaultTransport
+	}
+
+	var tr trace.Tracer
+	if t != nil {
+		tr = t.Tracer()
+	}
+	if tr == nil {
+		tr = otel.Tracer(instrumentationName)
+	}
+
+	return &transport{
+		tracer:     tr,
+		propagator: propagatorOf(t),
+		base:       base,
+	}
+}
+
+// propagatorOf returns the propagator of t, or the global one when t is
+// nil, so that tracers created with SkipGlobalRegistration still propagate.
+func propagatorOf(t Tracer) propagation.TextMapPropagator {
+	if t == nil {
+		return otel.GetTextMapPropagator()
+	}
+
+	return t.Propagator()
+}
+
+// WrapHTTPClient returns a copy of c whose transport is wrapped by
+// NewTransport using the global tracer provider. A nil c wraps
+// http.DefaultClient.
+func WrapHTTPClient(c *http.Client) *http.Client {
+	if c == nil {
+		c = http.DefaultClient
+	}
+
+	wrapped := *c
+	wrapped.Transport = NewTransport(nil, c.Transport)
+
+	return &wrapped
+}
+
+func (t *transport) RoundTrip(req *http.Request) (*http.Response, error) {
+	host := req.URL.Host
+	if host == "" {
+		host = req.Host
+	}
+
+	ctx, span := t.tracer.Start(
+		req.Context(),
+		fmt.Sprintf("%s %s", req.Method, host),
+		trace.WithSpanKind(trace.SpanKindClient),
+		trace.WithAttributes(httpconv.ClientRequest(req)...),
+	)
+	defer span.End()
+
+	// RoundTrippers must not modify the caller's request.
+	req = req.Clone(ctx)
+	t.propagator.Inject(ctx, propagation.HeaderCarrier(req.Header))
+
+	resp, err := t.base.RoundTrip(req)
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+		return resp, err
+	}
+
+	span.SetAttributes(httpconv.ClientResponse(resp)...)
+	span.SetStatus(httpconv.ClientStatus(resp.StatusCode))
+
+	return resp, nil
+}
@@ -0,0 +1,236 @@
+package tracer
+
+import (
+	"errors"
+	"fmt"
+	"log/slog"
+	"maps"
+	"os"
+	"path"
+	"slices"
+	"strings"
+	"time"
+
+	sdkTrace "go.opentelemetry.io/otel/sdk/trace"
+)
+
+// Validate checks the configuration of InitTracer up front and reports every
+// problem found, joined into one error: the endpoint and its scheme, the
+// service name, protocol and compression, the sampler spec and sampling
+// rules, span name and attribute key patterns, propagator and resource
+// detector names, the TLS files, header syntax and the batching sizes.
+func (c *Config) Validate() error {
+	var errs []error
+
+	if c.ExporterURL == "" {
+		errs = append(errs, fmt.Errorf("endpoint is missing in the otlp tracer configuration"))
+	} else if endpoint, err := parseEndpoint(c.ExporterURL); err != nil {
+		errs = append(errs, err)
+	} else {
+		switch endpoint.scheme {
+		case "", "http", "https":
+		case schemeUnix:
+			if c.Protocol == ProtocolHTTPProtobuf {
+				errs = append(errs, fmt.Errorf("unix socket endpoints require the %s protocol", ProtocolGRPC))
+			}
+		default:
+			errs = append(errs, fmt.Errorf("unsupported exporter URL scheme %q", endpoint.scheme))
+		}
+	}
+
+	if c.FailoverURL != "" {
+		if _, err := parseEndpoint(c.FailoverURL); err != nil {
+			errs = append(errs, fmt.Errorf("invalid FailoverURL: %w", err))
+		}
+	}
+
+	if resolveServiceName(c) == "" {
+		errs = append(errs, fmt.Errorf("service name is missing in the otlp tracer configuration"))
+	}
+
+	switch c.Protocol {
+	case "", ProtocolGRPC, ProtocolHTTPProtobuf:
+	default:
+		errs = append(errs, fmt.Errorf("unsupported otlp protocol %q", c.Protocol))
+	}
+
+	switch c.Compression {
+	case "", CompressionNone, CompressionGzip:
+	default:
+		errs = append(errs, fmt.Errorf("unsupported otlp compression %q", c.Compression))
+	}
+
+	if c.SamplerSpec != "" {
+		if _, err := ParseSampler(c.SamplerSpec); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	if len(c.SamplingRules) > 0 {
+		if _, err := newRuleSampler(c.SamplingRules, sdkTrace.AlwaysSample()); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	if _, err := newSpanNamePatterns(c.DropSpanNames); err != nil {
+		errs = append(errs, err)
+	}
+	if _, err := newExportFilter(c.ExportFilter); err != nil {
+		errs = append(errs, err)
+	}
+	for _, p := range slices.Concat(c.RedactAttributeKeys, c.HashAttributeKeys) {
+		if _, err := path.Match(p, ""); err != nil {
+			errs = append(errs, fmt.Errorf("invalid attribute key pattern %q: %w", p, err))
+		}
+	}
+
+	if _, err := newPropagator(resolvePropagators(c)); err != nil {
+		errs = append(errs, err)
+	}
+	if _, err := resourceDetectorOptions(c.ResourceDetectors); err != nil {
+		errs = append(errs, err)
+	}
+
+	for _, file := range []struct{ field, name string }{
+		{"TLSCAFile", c.TLSCAFile},
+		{"TLSCertFile", c.TLSCertFile},
+		{"TLSKeyFile", c.TLSKeyFile},
+	} {
+		if file.name == "" {
+			continue
+		}
+		if _, err := os.Stat(file.name); err != nil {
+			errs = append(errs, fmt.Errorf("invalid %s: %w", file.field, err))
+		}
+	}
+	if (c.TLSCertFile == "") != (c.TLSKeyFile == "") {
+		errs = append(errs, fmt.Errorf("TLSCertFile and TLSKeyFile must be set together"))
+	}
+
+	for key, value := range c.Headers {
+		if err := validateHeader(key, value); err != nil {
+			errs = append(errs, err)
+		}
+	}
+
+	for _, size := range []struct {
+		field string
+		value int64
+	}{
+		{"MaxQueueSize", int64(c.MaxQueueSize)},
+		{"MaxExportBatchSize", int64(c.MaxExportBatchSize)},
+		{"BatchTimeout", int64(c.BatchTimeout)},
+		{"ExportTimeout", int64(c.ExportTimeout)},
+		{"QueueDepthInterval", int64(c.QueueDepthInterval)},
+		{"MaxAttributeCardinality", int64(c.MaxAttributeCardinality)},
+		{"TailSamplingWindow", int64(c.TailSamplingWindow)},
+		{"MaxTraceDuration", int64(c.MaxTraceDuration)},
+		{"FailoverThreshold", int64(c.FailoverThreshold)},
+		{"FailoverRetryPrimary", int64(c.FailoverRetryPrimary)},
+		{"CircuitBreakerThreshold", int64(c.CircuitBreakerThreshold)},
+		{"CircuitBreakerCooldown", int64(c.CircuitBreakerCooldown)},
+		{"SpilloverMaxBytes", c.SpilloverMaxBytes},
+		{"SpilloverRetryInterval", int64(c.SpilloverRetryInterval)},
+	} {
+		if size.value < 0 {
+			errs = append(errs, fmt.Errorf("%s must not be negative", size.field))
+		}
+	}
+	if c.MaxQueueSize > 0 && c.MaxExportBatchSize > c.MaxQueueSize {
+		errs = append(errs, fmt.Errorf("MaxExportBatchSize %d exceeds MaxQueueSize %d", c.MaxExportBatchSize, c.MaxQueueSize))
+	}
+
+	return errors.Join(errs...)
+}
+
+// validateHeader rejects header names that are not HTTP tokens and values
+// with line breaks, which would fail every export request.
+func validateHeader(key, value string) error {
+	if key == "" {
+		return fmt.Errorf("invalid header: empty name")
+	}
+	for _, r := range key {
+		if r <= ' ' || r >= 0x7f || strings.ContainsRune(`"(),/:;<=>?@[\]{}`, r) {
+			return fmt.Errorf("invalid header name %q", key)
+		}
+	}
+	if strings.ContainsAny(value, "\r\n\x00") {
+		return fmt.Errorf("invalid value of header %q", key)
+	}
+
+	return nil
+}
+
+// EffectiveConfig returns a copy of the configuration with the values that
+// apply when the field is left unset filled in: the resolved service name,
+// protocol, compression, propagators, sampler spec, TLS mode, batching and
+// retry settings and logger. Hooks, credentials and processors are copied
+// as is. It does not validate the configuration.
+func (c *Config) EffectiveConfig() Config {
+	e := *c
+	e.Headers = maps.Clone(c.Headers)
+
+	e.ServiceName = resolveServiceName(c)
+	if e.Protocol == "" {
+		e.Protocol = ProtocolGRPC
+	}
+	if e.Compression == "" {
+		e.Compression = CompressionNone
+	}
+	e.Propagators = append([]string(nil), resolvePropagators(c)...)
+	if e.Sampler == nil && e.SamplerSpec == "" && e.TargetSpansPerSecond <= 0 {
+		e.SamplerSpec = "always_on"
+	}
+
+	if !e.Insecure {
+		scheme := ""
+		if endpoint, err := parseEndpoint(c.ExporterURL); err == nil {
+			scheme = endpoint.scheme
+		}
+		if creds, err := transportCredentials(c, scheme); err == nil && creds == nil {
+			e.Insecure = true
+		}
+	}
+
+	if e.MaxQueueSize == 0 {
+		e.MaxQueueSize = sdkTrace.DefaultMaxQueueSize
+	}
+	if e.MaxExportBatchSize == 0 {
+		e.MaxExportBatchSize = sdkTrace.DefaultMaxExportBatchSize
+	}
+	if e.BatchTimeout == 0 {
+		e.BatchTimeout = sdkTrace.DefaultScheduleDelay * time.Millisecond
+	}
+	if e.ExportTimeout == 0 {
+		e.ExportTimeout = sdkTrace.DefaultExportTimeout * time.Millisecond
+	}
+	if e.FailoverURL != "" && e.FailoverThreshold == 0 {
+		e.FailoverThreshold = defaultFailoverThreshold
+	}
+	if e.CircuitBreakerThreshold > 0 && e.CircuitBreakerCooldown == 0 {
+		e.CircuitBreakerCooldown = defaultCircuitBreakerCooldown
+	}
+	if e.SpilloverDir != "" && e.SpilloverMaxBytes == 0 {
+		e.SpilloverMaxBytes = defaultSpilloverMaxBytes
+	}
+	if e.SpilloverDir != "" && e.SpilloverRetryInterval == 0 {
+		e.SpilloverRetryInterval = defaultSpilloverRetryInterval
+	}
+	if e.QueueDepthInterval == 0 {
+		e.QueueDepthInterval = defaultQueueDepthInterval
+	}
+	if e.Retry == nil {
+		e.Retry = &RetryConfig{
+			Enabled:         true,
+			InitialInterval: 5 * time.Second,
+			MaxInterval:     30 * time.Second,
+			MaxElapsedTime:  time.Minute,
+		}
+	} else {
+		retry := *e.Retry
+		e.Retry = &retry
+	}
+	if e.Logger == nil {
+		e.Logger = slog.Default()
+	}
+
+	return e
+}